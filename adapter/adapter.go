@@ -7,34 +7,32 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"sort"
+	"strconv"
 
 	"github.com/code-game-project/cge-parser/parser"
 )
 
-// ParseMetadata reads the header of the CGE file and returns the metadata fields and a new io.Reader (even present if err != nil),
-// which still contains the header enabling it to be used for complete parsing.
+// ParseMetadata reads the complete CGE file and returns the metadata fields and a new io.Reader (even present if err != nil),
+// which contains the read data enabling it to be used for complete parsing.
 func ParseMetadata(file io.Reader) (Metadata, io.Reader, []Diagnostic, error) {
-	reader := newPeekReader(file, 1024)
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return Metadata{}, bytes.NewReader(data), nil, err
+	}
 
 	var metadata Metadata
 	diagnostics := make([]Diagnostic, 0)
 
-	err := parser.Parse(reader, &callbackSender{
+	err = parser.Parse(bytes.NewReader(data), &callbackSender{
 		CBMetadata: func(cgeVersion string) {
 			metadata = Metadata{
 				CGEVersion: cgeVersion,
 			}
 		},
-		CBDiagnostic: func(diagnosticType parser.DiagnosticType, message string, startLine, startCol, endLine, endCol int) {
-			if diagnosticType == parser.DiagnosticError {
-				diagnostics = append(diagnostics, Diagnostic{
-					Type:        DiagnosticType(diagnosticType),
-					Message:     message,
-					StartLine:   startLine,
-					StartColumn: startCol,
-					EndLine:     endLine,
-					EndColumn:   endCol,
-				})
+		CBDiagnostic: func(diagnostic parser.Diagnostic) {
+			if diagnostic.Type == parser.DiagnosticError {
+				diagnostics = append(diagnostics, diagnosticFromParser(diagnostic))
 			}
 		},
 	}, parser.Config{
@@ -47,14 +45,30 @@ func ParseMetadata(file io.Reader) (Metadata, io.Reader, []Diagnostic, error) {
 	if len(diagnostics) > 0 && err == nil {
 		err = errors.New("parsing error")
 	}
-	return metadata, io.MultiReader(reader.buffer, file), diagnostics, err
+	return metadata, bytes.NewReader(data), diagnostics, err
 }
 
 type Config struct {
-	IncludeComments bool
-	SendTokens      bool
-	NoObjects       bool
-	DisableWarnings bool
+	IncludeComments    bool
+	SendTokens         bool
+	SendSemanticTokens bool
+	NoObjects          bool
+	DisableWarnings    bool
+	BestEffort         bool
+	AssumeVersion      bool
+	ErrorTolerant      bool
+	// LintConfigFile is the path of a JSON lint config file.
+	LintConfigFile string
+	// LintRules overrides the severities of lint rules, e.g. {"unused-type": "error"}.
+	LintRules map[string]string
+	// Limits restricts the resources used for parsing untrusted input.
+	Limits parser.Limits
+	// PositionEncoding determines how columns are counted. Offsets are always counted in bytes.
+	PositionEncoding parser.PositionEncoding
+	// OneBased makes lines and columns start at 1 instead of 0.
+	OneBased bool
+	// MaxErrors limits the number of reported errors (0: unlimited).
+	MaxErrors int
 }
 
 func (c Config) toArgs() []string {
@@ -65,28 +79,90 @@ func (c Config) toArgs() []string {
 	if c.SendTokens {
 		args = append(args, "--tokens")
 	}
+	if c.SendSemanticTokens {
+		args = append(args, "--semantic-tokens")
+	}
 	if c.NoObjects {
 		args = append(args, "--no-objects")
 	}
 	if c.DisableWarnings {
 		args = append(args, "--no-warn")
 	}
+	if c.BestEffort {
+		args = append(args, "--best-effort")
+	}
+	if c.AssumeVersion {
+		args = append(args, "--assume-version")
+	}
+	if c.ErrorTolerant {
+		args = append(args, "--error-tolerant")
+	}
+	if c.LintConfigFile != "" {
+		args = append(args, "--lint-config", c.LintConfigFile)
+	}
+	rules := make([]string, 0, len(c.LintRules))
+	for rule := range c.LintRules {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		args = append(args, "--lint", rule+"="+c.LintRules[rule])
+	}
+	if c.PositionEncoding != parser.PositionUTF32 {
+		args = append(args, "--position-encoding", c.PositionEncoding.String())
+	}
+	if c.OneBased {
+		args = append(args, "--one-based")
+	}
+	intFlags := []struct {
+		flag  string
+		value int
+	}{
+		{"--max-bytes", c.Limits.MaxBytes},
+		{"--max-tokens", c.Limits.MaxTokens},
+		{"--max-nesting-depth", c.Limits.MaxNestingDepth},
+		{"--max-declarations", c.Limits.MaxDeclarations},
+		{"--max-identifier-length", c.Limits.MaxIdentifierLength},
+		{"--max-errors", c.MaxErrors},
+	}
+	for _, f := range intFlags {
+		if f.value > 0 {
+			args = append(args, f.flag, strconv.Itoa(f.value))
+		}
+	}
 	return args
 }
 
 type ParserResponse struct {
-	Metadata    Metadata
-	Config      Object
-	Events      []Object
-	Commands    []Object
-	Types       []Object
-	Enums       []Object
-	Tokens      []Token
-	Diagnostics []Diagnostic
+	Metadata       Metadata
+	Config         Object
+	Events         []Object
+	Commands       []Object
+	Types          []Object
+	Enums          []Object
+	Tokens         []Token
+	SemanticTokens []SemanticToken
+	Diagnostics    []Diagnostic
+	// Incomplete is true if the objects are a partial result of a file containing errors (only in error-tolerant mode).
+	Incomplete bool
 }
 
 func ParseCGE(file io.Reader, cgeParserPath string, config Config) (ParserResponse, []error) {
-	cmd := exec.Command(cgeParserPath, config.toArgs()...)
+	var response ParserResponse
+	errs := execute(file, cgeParserPath, config.toArgs(), func(output io.Reader) error {
+		var err error
+		response, err = receiveProtobufs(output, config)
+		return err
+	})
+	if errs != nil {
+		return ParserResponse{}, errs
+	}
+	return response, nil
+}
+
+// execute runs cge-parser with file as its input and passes its output to receive.
+func execute(file io.Reader, cgeParserPath string, args []string, receive func(output io.Reader) error) []error {
+	cmd := exec.Command(cgeParserPath, args...)
 	cmd.Stdin = file
 	reader, writer := io.Pipe()
 	cmd.Stdout = writer
@@ -96,13 +172,12 @@ func ParseCGE(file io.Reader, cgeParserPath string, config Config) (ParserRespon
 
 	err := cmd.Start()
 	if err != nil {
-		return ParserResponse{}, []error{fmt.Errorf("failed to start cge-parser: %w", err)}
+		return []error{fmt.Errorf("failed to start cge-parser: %w", err)}
 	}
 
-	var response ParserResponse
 	receiveDone := make(chan struct{})
 	go func() {
-		response, err = receiveProtobufs(reader, config)
+		err = receive(reader)
 		receiveDone <- struct{}{}
 	}()
 
@@ -121,15 +196,15 @@ func ParseCGE(file io.Reader, cgeParserPath string, config Config) (ParserRespon
 			if len(errs) == 0 {
 				errs = append(errs, fmt.Errorf("failed to execute cge-parser"))
 			}
-			return ParserResponse{}, errs
+			return errs
 		} else {
-			return ParserResponse{}, []error{fmt.Errorf("failed to execute cge-parser: %w", cmdErr)}
+			return []error{fmt.Errorf("failed to execute cge-parser: %w", cmdErr)}
 		}
 	}
 
 	if err != nil {
-		return ParserResponse{}, []error{fmt.Errorf("failed to receive data from cge-parser: %w", err)}
+		return []error{fmt.Errorf("failed to receive data from cge-parser: %w", err)}
 	}
 
-	return response, nil
+	return nil
 }
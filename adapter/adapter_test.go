@@ -0,0 +1,30 @@
+package adapter
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseMetadata(t *testing.T) {
+	source := "cge 0.5\n\nevent a { x: int32 }\n"
+	metadata, reader, diagnostics, err := ParseMetadata(strings.NewReader(source))
+	if err != nil || len(diagnostics) > 0 {
+		t.Fatalf("ParseMetadata() error = %v, diagnostics = %+v", err, diagnostics)
+	}
+	if metadata.CGEVersion != "0.5" {
+		t.Errorf("CGEVersion = %q, want 0.5", metadata.CGEVersion)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil || string(data) != source {
+		t.Errorf("returned reader = %q, %v, want the complete file", data, err)
+	}
+
+	_, reader, diagnostics, err = ParseMetadata(strings.NewReader("cge\nevent a {}\n"))
+	if err == nil || len(diagnostics) != 1 {
+		t.Errorf("ParseMetadata() with malformed header error = %v, diagnostics = %+v, want one diagnostic", err, diagnostics)
+	}
+	if data, _ := io.ReadAll(reader); string(data) != "cge\nevent a {}\n" {
+		t.Errorf("returned reader = %q after error, want the complete file", data)
+	}
+}
@@ -5,10 +5,11 @@ import (
 )
 
 type callbackSender struct {
-	CBMetadata   func(cgeVersion string)
-	CBDiagnostic func(diagnosticType parser.DiagnosticType, message string, startLine, startColumn, endLine, endColumn int)
-	CBToken      func(tokenType parser.TokenType, lexeme string, line, column int)
-	CBObject     func(object parser.Object)
+	CBMetadata      func(cgeVersion string)
+	CBDiagnostic    func(diagnostic parser.Diagnostic)
+	CBToken         func(token parser.Token)
+	CBSemanticToken func(token parser.SemanticToken)
+	CBObject        func(object parser.Object)
 }
 
 func (c *callbackSender) SendMetadata(cgeVersion string) error {
@@ -18,16 +19,23 @@ func (c *callbackSender) SendMetadata(cgeVersion string) error {
 	return nil
 }
 
-func (c *callbackSender) SendDiagnostic(diagnosticType parser.DiagnosticType, message string, startLine, startColumn, endLine, endColumn int) error {
+func (c *callbackSender) SendDiagnostic(diagnostic parser.Diagnostic) error {
 	if c.CBDiagnostic != nil {
-		c.CBDiagnostic(diagnosticType, message, startLine, startColumn, endLine, endColumn)
+		c.CBDiagnostic(diagnostic)
 	}
 	return nil
 }
 
-func (c *callbackSender) SendToken(tokenType parser.TokenType, lexeme string, line, column int) error {
+func (c *callbackSender) SendToken(token parser.Token) error {
 	if c.CBToken != nil {
-		c.CBToken(tokenType, lexeme, line, column)
+		c.CBToken(token)
+	}
+	return nil
+}
+
+func (c *callbackSender) SendSemanticToken(token parser.SemanticToken) error {
+	if c.CBSemanticToken != nil {
+		c.CBSemanticToken(token)
 	}
 	return nil
 }
@@ -0,0 +1,71 @@
+package adapter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/code-game-project/cge-parser/parser"
+	"github.com/code-game-project/cge-parser/protobuf"
+	"github.com/code-game-project/cge-parser/protobuf/schema"
+)
+
+// encode returns the messages in the format sent by cge-parser.
+func encode(t testing.TB, messages ...proto.Message) []byte {
+	var buf bytes.Buffer
+	for _, m := range messages {
+		if _, err := protodelim.MarshalTo(&buf, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// FuzzReceiveProtobufs checks that malformed output of cge-parser results in an error instead of a panic.
+func FuzzReceiveProtobufs(f *testing.F) {
+	var output bytes.Buffer
+	err := parser.Parse(strings.NewReader("cge 0.5\n// doc\nevent a { x: list<b>, y: c }\ntype b { z: enum d { e } }\n"), protobuf.NewSender(&output), parser.Config{
+		IncludeComments:    true,
+		SendTokens:         true,
+		SendSemanticTokens: true,
+		ErrorTolerant:      true,
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(output.Bytes())
+	f.Add(encode(f,
+		&schema.MsgType{Type: schema.MsgType_TOKEN}, &schema.Token{Lexeme: "a"},
+		&schema.MsgType{Type: schema.MsgType_SEMANTIC_TOKEN}, &schema.SemanticToken{Lexeme: "a"},
+		&schema.MsgType{Type: schema.MsgType_DIAGNOSTIC}, &schema.Diagnostic{
+			Related: []*schema.Diagnostic_Related{{Msg: "here"}},
+			Fixes:   []*schema.Diagnostic_Fix{{Edits: []*schema.Diagnostic_Fix_Edit{{Text: "x"}}}},
+		},
+		&schema.MsgType{Type: schema.MsgType_OBJECT}, &schema.Object{Type: schema.Object_EVENT, Properties: []*schema.Property{{Name: "x"}}},
+		&schema.MsgType{Type: schema.MsgType_OBJECT}, &schema.Object{Type: schema.Object_TYPE, Properties: []*schema.Property{{Type: &schema.Property_Type{
+			Type:        schema.Property_Type_CUSTOM,
+			Declaration: &schema.Symbol{Name: "b"},
+		}}}},
+	))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		receiveProtobufs(bytes.NewReader(data), Config{ErrorTolerant: true})
+	})
+}
+
+func FuzzReceiveQueryResponse(f *testing.F) {
+	f.Add(encode(f,
+		&schema.MsgType{Type: schema.MsgType_DEFINITION}, &schema.Definition{Name: "a"},
+		&schema.MsgType{Type: schema.MsgType_REFERENCES}, &schema.References{Locations: []*schema.Location{{}}},
+		&schema.MsgType{Type: schema.MsgType_HOVER}, &schema.Hover{Signature: "event a"},
+	))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		receiveQueryResponse(bytes.NewReader(data))
+	})
+}
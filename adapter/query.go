@@ -0,0 +1,136 @@
+package adapter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"github.com/code-game-project/cge-parser/protobuf/schema"
+)
+
+type QueryType int
+
+const (
+	QueryDefinition QueryType = iota
+	QueryReferences
+	QueryHover
+)
+
+func (q QueryType) flag() string {
+	switch q {
+	case QueryReferences:
+		return "--references"
+	case QueryHover:
+		return "--hover"
+	}
+	return "--definition"
+}
+
+type Location struct {
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+}
+
+type Definition struct {
+	Name     string
+	Location Location
+}
+
+type Hover struct {
+	Range     Location
+	Signature string
+	Doc       string
+}
+
+type QueryResponse struct {
+	// nil if there is no symbol at the position
+	Definition *Definition
+	// the declaration followed by all references
+	References []Location
+	// nil if there is no symbol at the position
+	Hover *Hover
+}
+
+// Query runs a definition, references or hover query for the zero-based position in the CGE file.
+func Query(file io.Reader, cgeParserPath string, queryType QueryType, line, column int) (QueryResponse, []error) {
+	var response QueryResponse
+	args := []string{"query", queryType.flag(), fmt.Sprintf("%d:%d", line, column)}
+	errs := execute(file, cgeParserPath, args, func(output io.Reader) error {
+		var err error
+		response, err = receiveQueryResponse(output)
+		return err
+	})
+	if errs != nil {
+		return QueryResponse{}, errs
+	}
+	return response, nil
+}
+
+func receiveQueryResponse(input io.Reader) (QueryResponse, error) {
+	in := bufio.NewReader(input)
+
+	response := QueryResponse{
+		References: make([]Location, 0),
+	}
+
+	for {
+		msgType := new(schema.MsgType)
+		err := protodelim.UnmarshalFrom(in, msgType)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return QueryResponse{}, fmt.Errorf("failed to decode protobuf message: %w", err)
+		}
+		switch msgType.Type {
+		case schema.MsgType_DEFINITION:
+			definition := new(schema.Definition)
+			err = protodelim.UnmarshalFrom(in, definition)
+			if err == nil && definition.Location != nil {
+				response.Definition = &Definition{
+					Name:     definition.Name,
+					Location: locationFromProtobuf(definition.Location),
+				}
+			}
+		case schema.MsgType_REFERENCES:
+			references := new(schema.References)
+			err = protodelim.UnmarshalFrom(in, references)
+			if err == nil {
+				for _, l := range references.Locations {
+					response.References = append(response.References, locationFromProtobuf(l))
+				}
+			}
+		case schema.MsgType_HOVER:
+			hover := new(schema.Hover)
+			err = protodelim.UnmarshalFrom(in, hover)
+			if err == nil && hover.Range != nil {
+				response.Hover = &Hover{
+					Range:     locationFromProtobuf(hover.Range),
+					Signature: hover.Signature,
+					Doc:       hover.Doc,
+				}
+			}
+		default:
+			err = fmt.Errorf("unexpected message type %s", msgType.Type)
+		}
+		if err != nil {
+			return QueryResponse{}, fmt.Errorf("failed to decode protobuf message: %w", err)
+		}
+	}
+
+	return response, nil
+}
+
+func locationFromProtobuf(location *schema.Location) Location {
+	return Location{
+		StartLine:   int(location.GetStart().GetLine()),
+		StartColumn: int(location.GetStart().GetColumn()),
+		EndLine:     int(location.GetEnd().GetLine()),
+		EndColumn:   int(location.GetEnd().GetColumn()),
+	}
+}
@@ -0,0 +1,50 @@
+package adapter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/code-game-project/cge-parser/parser"
+	"github.com/code-game-project/cge-parser/protobuf"
+	"github.com/code-game-project/cge-parser/query"
+)
+
+func TestReceiveQueryResponse(t *testing.T) {
+	token := parser.Token{Lexeme: "player", Line: 3, Column: 5, EndLine: 3, EndColumn: 11}
+	location := Location{StartLine: 3, StartColumn: 5, EndLine: 3, EndColumn: 11}
+	tests := []struct {
+		name string
+		send func(sender *protobuf.ProtobufSender) error
+		want QueryResponse
+	}{
+		{"definition", func(s *protobuf.ProtobufSender) error { return s.SendDefinition(&token) }, QueryResponse{Definition: &Definition{Name: "player", Location: location}}},
+		{"no definition", func(s *protobuf.ProtobufSender) error { return s.SendDefinition(nil) }, QueryResponse{}},
+		{"references", func(s *protobuf.ProtobufSender) error { return s.SendReferences([]parser.Token{token}) }, QueryResponse{References: []Location{location}}},
+		{"no references", func(s *protobuf.ProtobufSender) error { return s.SendReferences(nil) }, QueryResponse{}},
+		{"hover", func(s *protobuf.ProtobufSender) error {
+			return s.SendHover(&query.HoverInfo{Token: token, Signature: "type player", Doc: "A player."})
+		}, QueryResponse{Hover: &Hover{Range: location, Signature: "type player", Doc: "A player."}}},
+		{"no hover", func(s *protobuf.ProtobufSender) error { return s.SendHover(nil) }, QueryResponse{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := tt.send(protobuf.NewSender(&output)); err != nil {
+				t.Fatal(err)
+			}
+			got, err := receiveQueryResponse(&output)
+			if err != nil {
+				t.Fatalf("receiveQueryResponse() error = %v", err)
+			}
+			if (got.Definition == nil) != (tt.want.Definition == nil) || got.Definition != nil && *got.Definition != *tt.want.Definition {
+				t.Errorf("Definition = %+v, want %+v", got.Definition, tt.want.Definition)
+			}
+			if (got.Hover == nil) != (tt.want.Hover == nil) || got.Hover != nil && *got.Hover != *tt.want.Hover {
+				t.Errorf("Hover = %+v, want %+v", got.Hover, tt.want.Hover)
+			}
+			if len(got.References) != len(tt.want.References) || len(got.References) == 1 && got.References[0] != tt.want.References[0] {
+				t.Errorf("References = %+v, want %+v", got.References, tt.want.References)
+			}
+		})
+	}
+}
@@ -25,12 +25,13 @@ func receiveProtobufs(input io.Reader, config Config) (ParserResponse, error) {
 	}
 
 	response := ParserResponse{
-		Events:      make([]Object, 0, objCap),
-		Commands:    make([]Object, 0, objCap),
-		Types:       make([]Object, 0, objCap),
-		Enums:       make([]Object, 0, objCap),
-		Tokens:      make([]Token, 0, tokenCap),
-		Diagnostics: make([]Diagnostic, 0),
+		Events:         make([]Object, 0, objCap),
+		Commands:       make([]Object, 0, objCap),
+		Types:          make([]Object, 0, objCap),
+		Enums:          make([]Object, 0, objCap),
+		Tokens:         make([]Token, 0, tokenCap),
+		SemanticTokens: make([]SemanticToken, 0),
+		Diagnostics:    make([]Diagnostic, 0),
 	}
 
 	for {
@@ -55,6 +56,12 @@ func receiveProtobufs(input io.Reader, config Config) (ParserResponse, error) {
 			if err == nil {
 				response.Tokens = append(response.Tokens, tokenFromProtobuf(token))
 			}
+		case schema.MsgType_SEMANTIC_TOKEN:
+			token := new(schema.SemanticToken)
+			err = protodelim.UnmarshalFrom(in, token)
+			if err == nil {
+				response.SemanticTokens = append(response.SemanticTokens, semanticTokenFromProtobuf(token))
+			}
 		case schema.MsgType_OBJECT:
 			object := new(schema.Object)
 			err = protodelim.UnmarshalFrom(in, object)
@@ -84,5 +91,14 @@ func receiveProtobufs(input io.Reader, config Config) (ParserResponse, error) {
 		}
 	}
 
+	if config.ErrorTolerant {
+		for _, d := range response.Diagnostics {
+			if d.Type == DiagError {
+				response.Incomplete = true
+				break
+			}
+		}
+	}
+
 	return response, nil
 }
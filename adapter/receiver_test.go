@@ -0,0 +1,189 @@
+package adapter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/code-game-project/cge-parser/parser"
+	"github.com/code-game-project/cge-parser/protobuf"
+)
+
+// roundTrip parses source, sends the result as protobuf messages and receives them like ParseCGE.
+func roundTrip(t *testing.T, source string, config parser.Config) ParserResponse {
+	t.Helper()
+	var output bytes.Buffer
+	err := parser.Parse(strings.NewReader(source), protobuf.NewSender(&output), config)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	response, err := receiveProtobufs(&output, Config{ErrorTolerant: config.ErrorTolerant})
+	if err != nil {
+		t.Fatalf("receiveProtobufs() error = %v", err)
+	}
+	return response
+}
+
+// parseObjects returns the objects sent by the parser by name.
+func parseObjects(t *testing.T, source string, config parser.Config) map[string]parser.Object {
+	t.Helper()
+	objects := make(map[string]parser.Object)
+	err := parser.Parse(strings.NewReader(source), &callbackSender{
+		CBObject: func(object parser.Object) {
+			objects[object.Name.Lexeme] = object
+		},
+	}, config)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return objects
+}
+
+func findObject(objects []Object, name string) (Object, bool) {
+	for _, o := range objects {
+		if o.Name == name {
+			return o, true
+		}
+	}
+	return Object{}, false
+}
+
+func TestRoundTripDeclarations(t *testing.T) {
+	source := "cge 0.5\nevent joined {\n\tplayers: list<map<player>>,\n\thome: type base { team: team },\n\tghost: unknown,\n}\n// 😀\ntype player { username: string }\nenum team { red }\n"
+	config := parser.Config{ErrorTolerant: true, PositionEncoding: parser.PositionUTF16}
+	want := parseObjects(t, source, config)
+	response := roundTrip(t, source, config)
+
+	joined, ok := findObject(response.Events, "joined")
+	if !ok {
+		t.Fatalf("event joined not received")
+	}
+	base, ok := findObject(response.Types, "base")
+	if !ok {
+		t.Fatalf("type base not received")
+	}
+
+	tests := []struct {
+		name string
+		got  *PropertyType
+		want *parser.PropertyType
+	}{
+		{"joined.players", joined.Properties[0].Type.Generic.Generic, want["joined"].Properties[0].Type.Generic.Generic},
+		{"joined.home", joined.Properties[1].Type, want["joined"].Properties[1].Type},
+		{"joined.ghost", joined.Properties[2].Type, want["joined"].Properties[2].Type},
+		{"base.team", base.Properties[0].Type, want["base"].Properties[0].Type},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want.Declaration == nil {
+				if tt.got.Declaration != nil {
+					t.Errorf("Declaration = %+v, want nil", *tt.got.Declaration)
+				}
+				return
+			}
+			name := tt.want.Declaration.Name
+			kind := OTType
+			if tt.want.Declaration.Kind == parser.TTEnum {
+				kind = OTEnum
+			}
+			want := Symbol{
+				Kind:        kind,
+				Name:        name.Lexeme,
+				StartLine:   name.Line,
+				StartColumn: name.Column,
+				EndLine:     name.EndLine,
+				EndColumn:   name.EndColumn,
+				StartOffset: name.Offset,
+				EndOffset:   name.EndOffset,
+				Span:        tt.want.Declaration.Span,
+			}
+			if tt.got.Declaration == nil || *tt.got.Declaration != want {
+				t.Errorf("Declaration = %+v, want %+v", tt.got.Declaration, want)
+			}
+		})
+	}
+
+	// the list and map generics are not custom types
+	if d := joined.Properties[0].Type.Declaration; d != nil {
+		t.Errorf("Declaration of list = %+v, want nil", *d)
+	}
+}
+
+func TestRoundTripIncomplete(t *testing.T) {
+	tests := []struct {
+		name           string
+		source         string
+		wantIncomplete bool
+		wantEvents     string
+	}{
+		{"syntax error", "cge 0.5\nevent a { x: int32, y: }\nevent b { z: string }\n", true, "a incomplete [x], b [z]"},
+		{"semantic error", "cge 0.5\nevent a { x: undefined }\n", true, "a [x]"},
+		{"no errors", "cge 0.5\nevent a { x: int32 }\n", false, "a [x]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := roundTrip(t, tt.source, parser.Config{ErrorTolerant: true})
+			if response.Incomplete != tt.wantIncomplete {
+				t.Errorf("Incomplete = %t, want %t", response.Incomplete, tt.wantIncomplete)
+			}
+			events := make([]string, 0, len(response.Events))
+			for _, o := range response.Events {
+				names := make([]string, 0, len(o.Properties))
+				for _, p := range o.Properties {
+					names = append(names, p.Name)
+				}
+				summary := o.Name
+				if o.Incomplete {
+					summary += " incomplete"
+				}
+				events = append(events, fmt.Sprintf("%s %v", summary, names))
+			}
+			if got := strings.Join(events, ", "); got != tt.wantEvents {
+				t.Errorf("events = %q, want %q", got, tt.wantEvents)
+			}
+		})
+	}
+
+	response := roundTrip(t, "cge 0.5\nevent a { x: int32, y: }\n", parser.Config{})
+	if response.Incomplete || len(response.Events) != 0 {
+		t.Errorf("without error tolerance: Incomplete = %t, events = %+v, want no events", response.Incomplete, response.Events)
+	}
+}
+
+func TestRoundTripRelatedLocations(t *testing.T) {
+	source := "cge 0.5\n/* 😀 */ type t {}\nenum t { x }\nevent e { y: t, /* 😀 */ y: string }\n"
+	config := parser.Config{PositionEncoding: parser.PositionUTF16, OneBased: true, DisableWarnings: true}
+	var want []parser.Diagnostic
+	err := parser.Parse(strings.NewReader(source), &callbackSender{
+		CBDiagnostic: func(diagnostic parser.Diagnostic) {
+			want = append(want, diagnostic)
+		},
+	}, config)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(want) != 2 {
+		t.Fatalf("Parse() sent %d diagnostics, want 2", len(want))
+	}
+
+	response := roundTrip(t, source, config)
+	if len(response.Diagnostics) != len(want) {
+		t.Fatalf("received %d diagnostics, want %d", len(response.Diagnostics), len(want))
+	}
+	for i, d := range response.Diagnostics {
+		if len(d.Related) != 1 || len(want[i].Related) != 1 {
+			t.Fatalf("diagnostic %q has related locations %+v, want one", d.Message, d.Related)
+		}
+		if got, w := d.Related[0], RelatedLocation(want[i].Related[0]); got != w {
+			t.Errorf("related location of %q = %+v, want %+v", d.Message, got, w)
+		}
+	}
+	// the first declaration of t and the first property y
+	if r := response.Diagnostics[0].Related[0]; r.StartLine != 2 || r.StartColumn != 15 || r.Message != "first defined here" {
+		t.Errorf("related location of %q = %+v, want 2:15", response.Diagnostics[0].Message, r)
+	}
+	if r := response.Diagnostics[1].Related[0]; r.StartLine != 4 || r.StartColumn != 11 {
+		t.Errorf("related location of %q = %+v, want 4:11", response.Diagnostics[1].Message, r)
+	}
+}
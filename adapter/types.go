@@ -20,6 +20,8 @@ type Object struct {
 	Type       ObjectType
 	Comment    string
 	Properties []Property
+	// Incomplete is true if the declaration contains syntax errors.
+	Incomplete bool
 }
 
 type ObjectType int
@@ -48,6 +50,7 @@ func objectFromProtobuf(object *schema.Object) Object {
 		Type:       ObjectType(object.Type),
 		Comment:    *object.Comment,
 		Properties: properties,
+		Incomplete: object.Incomplete,
 	}
 }
 
@@ -61,6 +64,23 @@ type PropertyType struct {
 	Name    string
 	Type    DataType
 	Generic *PropertyType
+	// Declaration is the declaration of a DTCustom type (nil for other types).
+	Declaration *Symbol
+}
+
+// Symbol is the declaration of a custom type.
+type Symbol struct {
+	// OTType or OTEnum
+	Kind        ObjectType
+	Name        string
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+	StartOffset int
+	EndOffset   int
+	// Span is the span of the whole declaration from the keyword to the closing '}'.
+	Span parser.Span
 }
 
 type DataType int
@@ -92,14 +112,37 @@ func propertyFromProtobuf(property *schema.Property) Property {
 }
 
 func propertyTypeFromProtobuf(propertyType *schema.Property_Type) *PropertyType {
-	var generic *PropertyType
-	if propertyType.Generic != nil {
-		generic = propertyTypeFromProtobuf(propertyType.Generic)
+	if propertyType == nil {
+		return nil
+	}
+	var declaration *Symbol
+	if propertyType.Declaration != nil {
+		declaration = symbolFromProtobuf(propertyType.Declaration)
 	}
 	return &PropertyType{
-		Name:    propertyType.Name,
-		Type:    DataType(propertyType.Type),
-		Generic: generic,
+		Name:        propertyType.Name,
+		Type:        DataType(propertyType.Type),
+		Generic:     propertyTypeFromProtobuf(propertyType.Generic),
+		Declaration: declaration,
+	}
+}
+
+func symbolFromProtobuf(symbol *schema.Symbol) *Symbol {
+	return &Symbol{
+		Kind:        ObjectType(symbol.Kind),
+		Name:        symbol.Name,
+		StartLine:   int(symbol.GetStart().GetLine()),
+		StartColumn: int(symbol.GetStart().GetColumn()),
+		EndLine:     int(symbol.GetEnd().GetLine()),
+		EndColumn:   int(symbol.GetEnd().GetColumn()),
+		StartOffset: int(symbol.GetStart().GetOffset()),
+		EndOffset:   int(symbol.GetEnd().GetOffset()),
+		Span: parser.Span{
+			StartLine:   int(symbol.GetSpan().GetStart().GetLine()),
+			StartColumn: int(symbol.GetSpan().GetStart().GetColumn()),
+			EndLine:     int(symbol.GetSpan().GetEnd().GetLine()),
+			EndColumn:   int(symbol.GetSpan().GetEnd().GetColumn()),
+		},
 	}
 }
 
@@ -111,23 +154,145 @@ const (
 	DiagError   = DiagnosticType(schema.Diagnostic_ERROR)
 )
 
+type DiagnosticCategory int
+
+const (
+	DCSyntax      = DiagnosticCategory(schema.Diagnostic_SYNTAX)
+	DCSemantic    = DiagnosticCategory(schema.Diagnostic_SEMANTIC)
+	DCLint        = DiagnosticCategory(schema.Diagnostic_LINT)
+	DCDeprecation = DiagnosticCategory(schema.Diagnostic_DEPRECATION)
+)
+
 type Diagnostic struct {
-	Type        DiagnosticType
+	Type DiagnosticType
+	// stable identifier, e.g. CGE0012
+	Code string
+	// short name of the code, e.g. undefined-type
+	Name        string
+	Category    DiagnosticCategory
 	Message     string
 	StartLine   int
 	StartColumn int
 	EndLine     int
 	EndColumn   int
+	// byte offsets in the file
+	StartOffset int
+	EndOffset   int
+	// other locations, which are part of the problem
+	Related []RelatedLocation
+	// alternative changes to the source, which resolve the problem
+	Fixes []Fix
+}
+
+type Fix struct {
+	Title string
+	Edits []TextEdit
+	// Safe fixes do not change the meaning of the file and can be applied without review.
+	Safe bool
+}
+
+// TextEdit replaces the text between the start (inclusive) and the end (exclusive) with Text.
+type TextEdit struct {
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+	StartOffset int
+	EndOffset   int
+	Text        string
+}
+
+type RelatedLocation struct {
+	Message     string
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+	StartOffset int
+	EndOffset   int
 }
 
 func diagnosticFromProtobuf(diagnostic *schema.Diagnostic) Diagnostic {
+	related := make([]RelatedLocation, 0, len(diagnostic.Related))
+	for _, r := range diagnostic.Related {
+		related = append(related, RelatedLocation{
+			Message:     r.Msg,
+			StartLine:   int(r.GetStart().GetLine()),
+			StartColumn: int(r.GetStart().GetColumn()),
+			EndLine:     int(r.GetEnd().GetLine()),
+			EndColumn:   int(r.GetEnd().GetColumn()),
+			StartOffset: int(r.GetStart().GetOffset()),
+			EndOffset:   int(r.GetEnd().GetOffset()),
+		})
+	}
+	fixes := make([]Fix, 0, len(diagnostic.Fixes))
+	for _, f := range diagnostic.Fixes {
+		edits := make([]TextEdit, 0, len(f.Edits))
+		for _, e := range f.Edits {
+			edits = append(edits, TextEdit{
+				StartLine:   int(e.GetStart().GetLine()),
+				StartColumn: int(e.GetStart().GetColumn()),
+				EndLine:     int(e.GetEnd().GetLine()),
+				EndColumn:   int(e.GetEnd().GetColumn()),
+				StartOffset: int(e.GetStart().GetOffset()),
+				EndOffset:   int(e.GetEnd().GetOffset()),
+				Text:        e.Text,
+			})
+		}
+		fixes = append(fixes, Fix{
+			Title: f.Title,
+			Edits: edits,
+			Safe:  f.Safe,
+		})
+	}
 	return Diagnostic{
 		Type:        DiagnosticType(diagnostic.Type),
+		Code:        diagnostic.Code,
+		Name:        diagnostic.Name,
+		Category:    DiagnosticCategory(diagnostic.Category),
 		Message:     diagnostic.Msg,
-		StartLine:   int(diagnostic.Start.Line),
-		StartColumn: int(diagnostic.Start.Column),
-		EndLine:     int(diagnostic.End.Line),
-		EndColumn:   int(diagnostic.End.Column),
+		StartLine:   int(diagnostic.GetStart().GetLine()),
+		StartColumn: int(diagnostic.GetStart().GetColumn()),
+		EndLine:     int(diagnostic.GetEnd().GetLine()),
+		EndColumn:   int(diagnostic.GetEnd().GetColumn()),
+		StartOffset: int(diagnostic.GetStart().GetOffset()),
+		EndOffset:   int(diagnostic.GetEnd().GetOffset()),
+		Related:     related,
+		Fixes:       fixes,
+	}
+}
+
+func diagnosticFromParser(diagnostic parser.Diagnostic) Diagnostic {
+	related := make([]RelatedLocation, 0, len(diagnostic.Related))
+	for _, r := range diagnostic.Related {
+		related = append(related, RelatedLocation(r))
+	}
+	fixes := make([]Fix, 0, len(diagnostic.Fixes))
+	for _, f := range diagnostic.Fixes {
+		edits := make([]TextEdit, 0, len(f.Edits))
+		for _, e := range f.Edits {
+			edits = append(edits, TextEdit(e))
+		}
+		fixes = append(fixes, Fix{
+			Title: f.Title,
+			Edits: edits,
+			Safe:  f.Safe,
+		})
+	}
+	return Diagnostic{
+		Type:        DiagnosticType(diagnostic.Type),
+		Code:        diagnostic.Code.String(),
+		Name:        diagnostic.Code.Name(),
+		Category:    DiagnosticCategory(diagnostic.Code.Category()),
+		Message:     diagnostic.Message,
+		StartLine:   diagnostic.StartLine,
+		StartColumn: diagnostic.StartColumn,
+		EndLine:     diagnostic.EndLine,
+		EndColumn:   diagnostic.EndColumn,
+		StartOffset: diagnostic.StartOffset,
+		EndOffset:   diagnostic.EndOffset,
+		Related:     related,
+		Fixes:       fixes,
 	}
 }
 
@@ -136,13 +301,58 @@ type Token struct {
 	Lexeme string
 	Line   int
 	Column int
+	// byte offset in the file
+	Offset int
 }
 
 func tokenFromProtobuf(token *schema.Token) Token {
 	return Token{
 		Type:   parser.TokenType(token.Type),
 		Lexeme: token.Lexeme,
-		Line:   int(token.Pos.Line),
-		Column: int(token.Pos.Column),
+		Line:   int(token.GetPos().GetLine()),
+		Column: int(token.GetPos().GetColumn()),
+		Offset: int(token.GetPos().GetOffset()),
+	}
+}
+
+type SemanticTokenKind int
+
+const (
+	STGameName  = SemanticTokenKind(schema.SemanticToken_GAME_NAME)
+	STCommand   = SemanticTokenKind(schema.SemanticToken_COMMAND)
+	STEvent     = SemanticTokenKind(schema.SemanticToken_EVENT)
+	STType      = SemanticTokenKind(schema.SemanticToken_TYPE)
+	STEnum      = SemanticTokenKind(schema.SemanticToken_ENUM)
+	STEnumValue = SemanticTokenKind(schema.SemanticToken_ENUM_VALUE)
+	STProperty  = SemanticTokenKind(schema.SemanticToken_PROPERTY)
+	STUndefined = SemanticTokenKind(schema.SemanticToken_UNDEFINED)
+)
+
+type SemanticTokenRole int
+
+const (
+	SRDeclaration = SemanticTokenRole(schema.SemanticToken_DECLARATION)
+	SRReference   = SemanticTokenRole(schema.SemanticToken_REFERENCE)
+)
+
+type SemanticToken struct {
+	Kind       SemanticTokenKind
+	Role       SemanticTokenRole
+	Deprecated bool
+	Lexeme     string
+	Line       int
+	Column     int
+	Offset     int
+}
+
+func semanticTokenFromProtobuf(token *schema.SemanticToken) SemanticToken {
+	return SemanticToken{
+		Kind:       SemanticTokenKind(token.Kind),
+		Role:       SemanticTokenRole(token.Role),
+		Deprecated: token.Deprecated,
+		Lexeme:     token.Lexeme,
+		Line:       int(token.GetPos().GetLine()),
+		Column:     int(token.GetPos().GetColumn()),
+		Offset:     int(token.GetPos().GetOffset()),
 	}
 }
@@ -0,0 +1,79 @@
+package conformance
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// expectation is a diagnostic expected by an annotation.
+type expectation struct {
+	Severity Severity
+	// zero-based line of the diagnostic
+	Line int
+	// Text must be contained in the message of the diagnostic.
+	Text string
+}
+
+var annotationStart = regexp.MustCompile(`//\s*(ERROR|WARN|INFO)\s`)
+
+// parseAnnotations removes all annotations from source and returns the remaining source and the expectations.
+// The positions of the remaining code are not changed.
+func parseAnnotations(source string) (string, []expectation, error) {
+	lines := strings.Split(source, "\n")
+	var expectations []expectation
+	for i, line := range lines {
+		loc := annotationStart.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		code := strings.TrimRight(line[:loc[0]], " \t")
+		annotation := strings.TrimSuffix(line[loc[0]+2:], "\r")
+		if strings.HasSuffix(line, "\r") {
+			lines[i] = code + "\r"
+		} else {
+			lines[i] = code
+		}
+
+		target := i
+		if strings.TrimSpace(code) == "" {
+			target = i + 1
+		}
+		expected, err := parseAnnotation(annotation, target)
+		if err != nil {
+			return "", nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		expectations = append(expectations, expected...)
+	}
+	return strings.Join(lines, "\n"), expectations, nil
+}
+
+// parseAnnotation parses a list of severities followed by quoted strings like `ERROR "a" WARN "b"`.
+func parseAnnotation(annotation string, line int) ([]expectation, error) {
+	var expectations []expectation
+	rest := strings.TrimSpace(annotation)
+	for rest != "" {
+		keyword, text, _ := strings.Cut(rest, " ")
+		severity, ok := parseSeverity(keyword)
+		if !ok {
+			return nil, fmt.Errorf("invalid annotation '%s': expected ERROR, WARN or INFO", keyword)
+		}
+		text = strings.TrimSpace(text)
+		quoted, err := strconv.QuotedPrefix(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation: expected quoted message after %s", keyword)
+		}
+		message, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation: %w", err)
+		}
+		expectations = append(expectations, expectation{
+			Severity: severity,
+			Line:     line,
+			Text:     message,
+		})
+		rest = strings.TrimSpace(text[len(quoted):])
+	}
+	return expectations, nil
+}
@@ -0,0 +1,268 @@
+// Package conformance checks CGE implementations against a corpus of annotated CGE files.
+//
+// Every test case is a .cge file. Expected diagnostics are annotated with line comments:
+//
+//	event scored { points: pionts } // ERROR "undefined type 'pionts'"
+//
+// An annotation is ERROR, WARN or INFO followed by a quoted string, which must be contained in the message
+// of a diagnostic with that severity starting on the line of the annotation. A comment may contain multiple annotations.
+// Annotations on a line without code apply to the next line.
+// Every diagnostic must be matched by an annotation.
+// The annotations are removed before the source is passed to the implementation.
+// All other positions in the file are not changed.
+//
+// If file.golden exists next to file.cge, the objects returned by the implementation must match its JSON content.
+// Objects are compared ordered by kind and name.
+package conformance
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+//go:embed corpus
+var corpus embed.FS
+
+// Corpus contains the conformance test cases of the CGE language.
+var Corpus fs.FS
+
+func init() {
+	var err error
+	Corpus, err = fs.Sub(corpus, "corpus")
+	if err != nil {
+		panic(err)
+	}
+}
+
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "INFO"
+	case Warning:
+		return "WARN"
+	case Error:
+		return "ERROR"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+func parseSeverity(keyword string) (Severity, bool) {
+	switch keyword {
+	case "INFO":
+		return Info, true
+	case "WARN":
+		return Warning, true
+	case "ERROR":
+		return Error, true
+	}
+	return 0, false
+}
+
+type Diagnostic struct {
+	Severity Severity
+	// zero-based
+	Line    int
+	Column  int
+	Message string
+}
+
+type Object struct {
+	// config, command, event, type or enum
+	Kind       string     `json:"kind"`
+	Name       string     `json:"name"`
+	Comment    string     `json:"comment,omitempty"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+type Property struct {
+	Name string `json:"name"`
+	// e.g. map<list<int64>> (empty for enum values)
+	Type    string `json:"type,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Result is the output of an implementation for a single file.
+type Result struct {
+	Diagnostics []Diagnostic
+	// Objects is only compared if a golden file exists.
+	Objects []Object
+}
+
+// Implementation parses a CGE file.
+type Implementation func(source string) (Result, error)
+
+// Run runs all test cases in corpus as subtests.
+func Run(t *testing.T, corpus fs.FS, impl Implementation) {
+	t.Helper()
+	files, err := testCases(corpus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("corpus does not contain any .cge files")
+	}
+	for _, name := range files {
+		t.Run(strings.TrimSuffix(name, ".cge"), func(t *testing.T) {
+			runTestCase(t, corpus, name, impl)
+		})
+	}
+}
+
+func runTestCase(t *testing.T, corpus fs.FS, name string, impl Implementation) {
+	data, err := fs.ReadFile(corpus, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, expectations, err := parseAnnotations(string(data))
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	result, err := impl(source)
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	for _, problem := range checkDiagnostics(result.Diagnostics, expectations) {
+		t.Errorf("%s:%s", name, problem)
+	}
+
+	golden, err := fs.ReadFile(corpus, goldenName(name))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		return
+	}
+	got, err := encodeObjects(result.Objects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden = bytes.ReplaceAll(golden, []byte("\r\n"), []byte("\n"))
+	if !bytes.Equal(got, golden) {
+		t.Errorf("%s: objects do not match %s:\n%s\nwant:\n%s", name, goldenName(name), got, golden)
+	}
+}
+
+// checkDiagnostics matches every diagnostic with an expectation and returns all unmatched diagnostics and expectations.
+func checkDiagnostics(diagnostics []Diagnostic, expectations []expectation) []string {
+	var problems []string
+	matched := make([]bool, len(expectations))
+	for _, d := range diagnostics {
+		found := false
+		for i, e := range expectations {
+			if !matched[i] && e.Line == d.Line && e.Severity == d.Severity && strings.Contains(d.Message, e.Text) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%d:%d: unexpected %s %q", d.Line+1, d.Column+1, d.Severity, d.Message))
+		}
+	}
+	for i, e := range expectations {
+		if !matched[i] {
+			problems = append(problems, fmt.Sprintf("%d: missing %s %q", e.Line+1, e.Severity, e.Text))
+		}
+	}
+	return problems
+}
+
+// Update writes the golden files of all test cases in dir, which do not produce any errors.
+func Update(dir string, impl Implementation) error {
+	files, err := testCases(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+		source, _, err := parseAnnotations(string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		result, err := impl(source)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if hasErrors(result.Diagnostics) {
+			continue
+		}
+		golden, err := encodeObjects(result.Objects)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(filepath.Join(dir, filepath.FromSlash(goldenName(name))), golden, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to write golden file: %w", err)
+		}
+	}
+	return nil
+}
+
+// testCases returns the paths of all .cge files in corpus.
+func testCases(corpus fs.FS) ([]string, error) {
+	var files []string
+	err := fs.WalkDir(corpus, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && path.Ext(name) == ".cge" {
+			files = append(files, name)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func goldenName(name string) string {
+	return strings.TrimSuffix(name, ".cge") + ".golden"
+}
+
+func hasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeObjects returns the golden file content of objects.
+func encodeObjects(objects []Object) ([]byte, error) {
+	sorted := make([]Object, len(objects))
+	copy(sorted, objects)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Kind != sorted[j].Kind {
+			return sorted[i].Kind < sorted[j].Kind
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	err := encoder.Encode(sorted)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package conformance
+
+import (
+	"flag"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the corpus")
+
+func TestCorpus(t *testing.T) {
+	if *update {
+		if err := Update("corpus", Parse); err != nil {
+			t.Fatal(err)
+		}
+	}
+	Run(t, os.DirFS("corpus"), Parse)
+}
+
+func TestParseAnnotations(t *testing.T) {
+	source := "event a { // ERROR \"x\" WARN \"y \\\"z\\\"\"\r\n\tb: c,\n// INFO \"next line\"\n}"
+	got, expectations, err := parseAnnotations(source)
+	if err != nil {
+		t.Fatalf("parseAnnotations() error = %v", err)
+	}
+	if want := "event a {\r\n\tb: c,\n\n}"; got != want {
+		t.Errorf("source = %q, want %q", got, want)
+	}
+	want := []expectation{{Error, 0, "x"}, {Warning, 0, `y "z"`}, {Info, 3, "next line"}}
+	if len(expectations) != len(want) {
+		t.Fatalf("expectations = %+v, want %+v", expectations, want)
+	}
+	for i := range want {
+		if expectations[i] != want[i] {
+			t.Errorf("expectations[%d] = %+v, want %+v", i, expectations[i], want[i])
+		}
+	}
+
+	if _, _, err := parseAnnotations("a // ERROR x"); err == nil {
+		t.Errorf("parseAnnotations() with unquoted message: expected error")
+	}
+}
+
+func TestCheckDiagnostics(t *testing.T) {
+	diagnostics := []Diagnostic{
+		{Severity: Error, Line: 1, Message: "undefined type 'a'"},
+		{Severity: Warning, Line: 2, Message: "type 'b' is never used"},
+		{Severity: Error, Line: 3, Message: "expected '}'"},
+	}
+	expectations := []expectation{
+		{Error, 1, "undefined type"},
+		{Error, 2, "never used"},
+		{Error, 4, "expected"},
+	}
+	want := []string{
+		`3:1: unexpected WARN "type 'b' is never used"`,
+		`4:1: unexpected ERROR "expected '}'"`,
+		`3: missing ERROR "never used"`,
+		`5: missing ERROR "expected"`,
+	}
+	got := checkDiagnostics(diagnostics, expectations)
+	if len(got) != len(want) {
+		t.Fatalf("checkDiagnostics() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("checkDiagnostics()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
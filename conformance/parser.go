@@ -0,0 +1,66 @@
+package conformance
+
+import (
+	"strings"
+
+	"github.com/code-game-project/cge-parser/parser"
+	"github.com/code-game-project/cge-parser/query"
+)
+
+// Parse is the Implementation of the parser package with the default configuration.
+func Parse(source string) (Result, error) {
+	sender := &resultSender{}
+	err := parser.Parse(strings.NewReader(source), sender, parser.Config{
+		IncludeComments: true,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return sender.result, nil
+}
+
+// resultSender collects the diagnostics and objects sent by the parser.
+type resultSender struct {
+	result Result
+}
+
+func (r *resultSender) SendMetadata(version string) error { return nil }
+
+func (r *resultSender) SendDiagnostic(diagnostic parser.Diagnostic) error {
+	severity := Info
+	switch diagnostic.Type {
+	case parser.DiagnosticWarning:
+		severity = Warning
+	case parser.DiagnosticError:
+		severity = Error
+	}
+	r.result.Diagnostics = append(r.result.Diagnostics, Diagnostic{
+		Severity: severity,
+		Line:     diagnostic.StartLine,
+		Column:   diagnostic.StartColumn,
+		Message:  diagnostic.Message,
+	})
+	return nil
+}
+
+func (r *resultSender) SendToken(token parser.Token) error { return nil }
+
+func (r *resultSender) SendSemanticToken(token parser.SemanticToken) error { return nil }
+
+func (r *resultSender) SendObject(object parser.Object) error {
+	properties := make([]Property, 0, len(object.Properties))
+	for _, p := range object.Properties {
+		properties = append(properties, Property{
+			Name:    p.Name,
+			Type:    query.TypeString(p.Type),
+			Comment: query.CommentText(p.Comment),
+		})
+	}
+	r.result.Objects = append(r.result.Objects, Object{
+		Kind:       object.Keyword(),
+		Name:       object.Name.Lexeme,
+		Comment:    query.CommentText(object.Comment),
+		Properties: properties,
+	})
+	return nil
+}
@@ -0,0 +1,181 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around every change.
+const contextLines = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	// line indices in a and b before the operation
+	a, b int
+	text string
+}
+
+// Unified returns the changes from a to b in the unified diff format or an empty string if they are equal.
+func Unified(fromName, toName, a, b string) string {
+	if a == b {
+		return ""
+	}
+	ops := lineOps(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(ops); {
+		// find the next change
+		for start < len(ops) && ops[start].kind == opEqual {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		hunkStart := start - contextLines
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+
+		// extend the hunk until there are more than 2*contextLines unchanged lines
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*contextLines {
+				break
+			}
+			end = next
+		}
+		hunkEnd := end + contextLines
+		if hunkEnd > len(ops) {
+			hunkEnd = len(ops)
+		}
+
+		writeHunk(&out, ops[hunkStart:hunkEnd])
+		start = hunkEnd
+	}
+	return out.String()
+}
+
+func writeHunk(out *strings.Builder, ops []op) {
+	var aCount, bCount int
+	for _, o := range ops {
+		if o.kind != opInsert {
+			aCount++
+		}
+		if o.kind != opDelete {
+			bCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(ops[0].a, aCount), hunkRange(ops[0].b, bCount))
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			out.WriteByte(' ')
+		case opDelete:
+			out.WriteByte('-')
+		case opInsert:
+			out.WriteByte('+')
+		}
+		out.WriteString(o.text)
+		if !strings.HasSuffix(o.text, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits text after every newline. The newlines are kept.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineOps computes a minimal edit script of the lines.
+// The common prefix and suffix are skipped before computing the longest common subsequence of the remaining lines,
+// so that the quadratic table only covers the changed region.
+func lineOps(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{kind: opEqual, a: i, b: i, text: a[i]})
+	}
+	ops = lcsOps(ops, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix)
+	for k := 0; k < suffix; k++ {
+		i, j := len(a)-suffix+k, len(b)-suffix+k
+		ops = append(ops, op{kind: opEqual, a: i, b: j, text: a[i]})
+	}
+	return ops
+}
+
+// lcsOps appends the edit script of a and b using their longest common subsequence to ops.
+// offset is the index of the first line of a and b in the complete input.
+func lcsOps(ops []op, a, b []string, offset int) []op {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{kind: opEqual, a: offset + i, b: offset + j, text: a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{kind: opDelete, a: offset + i, b: offset + j, text: a[i]})
+			i++
+		default:
+			ops = append(ops, op{kind: opInsert, a: offset + i, b: offset + j, text: b[j]})
+			j++
+		}
+	}
+	return ops
+}
@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+// lines returns the arguments as newline terminated lines.
+func lines(l ...string) string {
+	return strings.Join(l, "\n") + "\n"
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"empty", "", "", ""},
+		{
+			"context",
+			lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10"),
+			lines("1", "2", "3", "4", "x", "6", "7", "8", "9", "10"),
+			"--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n",
+		},
+		{
+			"merged hunks",
+			lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"),
+			lines("1", "x", "3", "4", "5", "6", "7", "8", "y", "10", "11", "12"),
+			"--- a\n+++ b\n@@ -1,12 +1,12 @@\n 1\n-2\n+x\n 3\n 4\n 5\n 6\n 7\n 8\n-9\n+y\n 10\n 11\n 12\n",
+		},
+		{
+			"separate hunks",
+			lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"),
+			lines("1", "x", "3", "4", "5", "6", "7", "8", "9", "y", "11", "12"),
+			"--- a\n+++ b\n@@ -1,5 +1,5 @@\n 1\n-2\n+x\n 3\n 4\n 5\n@@ -7,6 +7,6 @@\n 7\n 8\n 9\n-10\n+y\n 11\n 12\n",
+		},
+		{
+			"no trailing newline",
+			"a\nb", "a\nc",
+			"--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			"added trailing newline",
+			"a\nb", "a\nb\n",
+			"--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{"from empty", "", "x\n", "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n"},
+		{"to empty", "x\n", "", "--- a\n+++ b\n@@ -1 +0,0 @@\n-x\n"},
+		{"CRLF", "a\r\nb\r\n", "a\r\nc\r\n", "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\r\n-b\r\n+c\r\n"},
+		{"LF to CRLF", "a\nb\n", "a\r\nb\r\n", "--- a\n+++ b\n@@ -1,2 +1,2 @@\n-a\n-b\n+a\r\n+b\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified("a", "b", tt.a, tt.b); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineOps(t *testing.T) {
+	a := splitLines(lines("1", "2", "3", "4", "5"))
+	b := splitLines(lines("1", "x", "3", "y", "4", "5"))
+	want := []op{
+		{opEqual, 0, 0, "1\n"},
+		{opDelete, 1, 1, "2\n"},
+		{opInsert, 2, 1, "x\n"},
+		{opEqual, 2, 2, "3\n"},
+		{opInsert, 3, 3, "y\n"},
+		{opEqual, 3, 4, "4\n"},
+		{opEqual, 4, 5, "5\n"},
+	}
+	got := lineOps(a, b)
+	if len(got) != len(want) {
+		t.Fatalf("lineOps() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("lineOps()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package lsp
+
+import (
+	"github.com/code-game-project/cge-parser/parser"
+	"github.com/code-game-project/cge-parser/query"
+)
+
+func (s *Server) completion(params TextDocumentPositionParams) ([]CompletionItem, error) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	line, column := params.Position.parserPos()
+	a := doc.analysis
+
+	items := make([]CompletionItem, 0)
+	prev, depth := a.context(line, column)
+	switch {
+	case prev != nil && (prev.Type == parser.TTColon || prev.Type == parser.TTLess):
+		for _, t := range parser.PrimitiveTypes() {
+			items = append(items, CompletionItem{
+				Label:  t,
+				Kind:   CompletionKindTypeParam,
+				Detail: "primitive type",
+			})
+		}
+		if prev.Type == parser.TTColon {
+			for _, k := range []string{"type", "enum"} {
+				items = append(items, CompletionItem{
+					Label:  k,
+					Kind:   CompletionKindKeyword,
+					Detail: "inline declaration",
+				})
+			}
+		}
+		for _, o := range a.Objects() {
+			if o.Type != parser.TTType && o.Type != parser.TTEnum {
+				continue
+			}
+			kind := CompletionKindStruct
+			if o.Type == parser.TTEnum {
+				kind = CompletionKindEnum
+			}
+			item := CompletionItem{
+				Label:  o.Name.Lexeme,
+				Kind:   kind,
+				Detail: o.Keyword() + " " + o.Name.Lexeme,
+			}
+			if text := query.CommentText(o.Comment); text != "" {
+				item.Documentation = &MarkupContent{
+					Kind:  "markdown",
+					Value: text,
+				}
+			}
+			items = append(items, item)
+		}
+	case depth == 0:
+		for _, k := range parser.DeclarationKeywords() {
+			items = append(items, CompletionItem{
+				Label: k,
+				Kind:  CompletionKindKeyword,
+			})
+		}
+		if prev == nil {
+			items = append(items, CompletionItem{
+				Label: "cge",
+				Kind:  CompletionKindKeyword,
+			})
+		}
+	}
+	return items, nil
+}
+
+// context returns the last token that ends before the word at the position and the block nesting depth at that token.
+func (a *analysis) context(line, column int) (*parser.Token, int) {
+	var prev *parser.Token
+	depth := 0
+	for i := range a.tokens {
+		t := &a.tokens[i]
+		if t.Line > line || (t.Line == line && t.Column >= column) {
+			break
+		}
+		if t.Type == parser.TTComment {
+			if query.ContainsPos(*t, line, column) {
+				return nil, -1
+			}
+			continue
+		}
+		if query.ContainsPos(*t, line, column) && t.Type != parser.TTColon && t.Type != parser.TTLess &&
+			t.Type != parser.TTOpenCurly && t.Type != parser.TTCloseCurly && t.Type != parser.TTComma {
+			break
+		}
+		switch t.Type {
+		case parser.TTOpenCurly:
+			depth++
+		case parser.TTCloseCurly:
+			depth--
+		}
+		prev = t
+	}
+	return prev, depth
+}
@@ -0,0 +1,144 @@
+package lsp
+
+import (
+	"strings"
+
+	"github.com/code-game-project/cge-parser/parser"
+	"github.com/code-game-project/cge-parser/query"
+)
+
+type document struct {
+	uri      string
+	version  int
+	text     string
+	tree     *parser.Tree
+	analysis *analysis
+}
+
+func newDocument(uri string, version int, text string) (*document, error) {
+	d := &document{
+		uri: uri,
+	}
+	err := d.replace(version, text)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *document) replace(version int, text string) error {
+	tree, err := parser.ParseTree(strings.NewReader(text), parser.Config{
+		IncludeComments:  true,
+		PositionEncoding: parser.PositionUTF16,
+	})
+	if err != nil {
+		return err
+	}
+	d.setTree(version, tree)
+	return nil
+}
+
+// edit applies an incremental change and reparses only the affected declarations.
+func (d *document) edit(version int, r Range, text string) error {
+	tree, _, err := d.tree.Edit(parser.Edit{
+		StartLine:   r.Start.Line,
+		StartColumn: r.Start.Character,
+		EndLine:     r.End.Line,
+		EndColumn:   r.End.Character,
+		Text:        text,
+	})
+	if err != nil {
+		return err
+	}
+	d.setTree(version, tree)
+	return nil
+}
+
+func (d *document) setTree(version int, tree *parser.Tree) {
+	d.version = version
+	d.tree = tree
+	d.text = tree.Source()
+	d.analysis = analyze(tree)
+}
+
+// parserPos returns the position as parser line and column. Documents are parsed with UTF-16 columns like LSP positions.
+func (p Position) parserPos() (line, column int) {
+	return p.Line, p.Character
+}
+
+func lspPos(line, column int) Position {
+	return Position{Line: line, Character: column}
+}
+
+func (d *document) tokenRange(token parser.Token) Range {
+	return Range{
+		Start: lspPos(token.Line, token.Column),
+		End:   lspPos(token.EndLine, token.EndColumn),
+	}
+}
+
+func (d *document) location(token parser.Token) Location {
+	return Location{
+		URI:   d.uri,
+		Range: d.tokenRange(token),
+	}
+}
+
+type analysis struct {
+	*query.Index
+	tokens         []parser.Token
+	semanticTokens []parser.SemanticToken
+	diagnostics    []parser.Diagnostic
+}
+
+func analyze(tree *parser.Tree) *analysis {
+	a := &analysis{
+		Index:          query.NewIndex(tree.Objects()),
+		tokens:         tree.Tokens(),
+		semanticTokens: tree.SemanticTokens(),
+		diagnostics:    tree.Diagnostics(),
+	}
+	return a
+}
+
+func (d *document) lspDiagnostic(diagnostic parser.Diagnostic) Diagnostic {
+	severity := SeverityInformation
+	switch diagnostic.Type {
+	case parser.DiagnosticError:
+		severity = SeverityError
+	case parser.DiagnosticWarning:
+		severity = SeverityWarning
+	}
+	var tags []DiagnosticTag
+	if diagnostic.Code.Category() == parser.CategoryDeprecation {
+		tags = []DiagnosticTag{DiagnosticTagDeprecated}
+	} else if diagnostic.Code == parser.CodeUnusedSuppression {
+		tags = []DiagnosticTag{DiagnosticTagUnnecessary}
+	}
+	var related []DiagnosticRelatedInformation
+	for _, r := range diagnostic.Related {
+		related = append(related, DiagnosticRelatedInformation{
+			Location: Location{
+				URI: d.uri,
+				Range: Range{
+					Start: lspPos(r.StartLine, r.StartColumn),
+					End:   lspPos(r.EndLine, r.EndColumn),
+				},
+			},
+			Message: r.Message,
+		})
+	}
+	return Diagnostic{
+		Range: Range{
+			Start: lspPos(diagnostic.StartLine, diagnostic.StartColumn),
+			End:   lspPos(diagnostic.EndLine, diagnostic.EndColumn),
+		},
+		Severity: severity,
+		Code:     diagnostic.Code.String(),
+		Source:   "cge",
+		Message:  diagnostic.Message,
+		Tags:     tags,
+
+		RelatedInformation: related,
+	}
+}
@@ -0,0 +1,60 @@
+package lsp
+
+// codeActions returns the fixes of all diagnostics overlapping the requested range.
+func (s *Server) codeActions(params CodeActionParams) ([]CodeAction, error) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	if len(params.Context.Only) > 0 && !containsString(params.Context.Only, CodeActionQuickFix) {
+		return []CodeAction{}, nil
+	}
+
+	actions := make([]CodeAction, 0)
+	for _, d := range doc.analysis.diagnostics {
+		if len(d.Fixes) == 0 {
+			continue
+		}
+		diagnostic := doc.lspDiagnostic(d)
+		if positionBefore(diagnostic.Range.End, params.Range.Start) || positionBefore(params.Range.End, diagnostic.Range.Start) {
+			continue
+		}
+		for i, f := range d.Fixes {
+			edits := make([]TextEdit, 0, len(f.Edits))
+			for _, e := range f.Edits {
+				edits = append(edits, TextEdit{
+					Range: Range{
+						Start: lspPos(e.StartLine, e.StartColumn),
+						End:   lspPos(e.EndLine, e.EndColumn),
+					},
+					NewText: e.Text,
+				})
+			}
+			actions = append(actions, CodeAction{
+				Title:       f.Title,
+				Kind:        CodeActionQuickFix,
+				Diagnostics: []Diagnostic{diagnostic},
+				IsPreferred: i == 0,
+				Edit: &WorkspaceEdit{
+					Changes: map[string][]TextEdit{
+						doc.uri: edits,
+					},
+				},
+			})
+		}
+	}
+	return actions, nil
+}
+
+func positionBefore(a, b Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+func containsString(list []string, value string) bool {
+	for _, s := range list {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeRequestFailed  = -32803
+)
+
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  any              `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (r *responseError) Error() string {
+	return r.Message
+}
+
+type conn struct {
+	in  *textproto.Reader
+	out io.Writer
+	mu  sync.Mutex
+}
+
+func newConn(in io.Reader, out io.Writer) *conn {
+	return &conn{
+		in:  textproto.NewReader(bufio.NewReader(in)),
+		out: out,
+	}
+}
+
+func (c *conn) read() (*message, error) {
+	header, err := c.in.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header: %d", length)
+	}
+
+	// the body is not allocated upfront to avoid huge allocations for bogus lengths
+	body, err := io.ReadAll(io.LimitReader(c.in.R, int64(length)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message body: %w", err)
+	}
+	if len(body) < length {
+		return nil, fmt.Errorf("failed to read message body: %w", io.ErrUnexpectedEOF)
+	}
+
+	msg := new(message)
+	err = json.Unmarshal(body, msg)
+	if err != nil {
+		return nil, &responseError{Code: codeParseError, Message: err.Error()}
+	}
+	return msg, nil
+}
+
+func (c *conn) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = fmt.Fprintf(c.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+// reply sends a response. The id is null if the id of the request could not be read.
+func (c *conn) reply(id *json.RawMessage, result any, err error) error {
+	if id == nil {
+		null := json.RawMessage("null")
+		id = &null
+	}
+	msg := &message{
+		ID: id,
+	}
+	if err != nil {
+		rerr, ok := err.(*responseError)
+		if !ok {
+			rerr = &responseError{Code: codeRequestFailed, Message: err.Error()}
+		}
+		msg.Error = rerr
+	} else {
+		if result == nil {
+			result = json.RawMessage("null")
+		}
+		msg.Result = result
+	}
+	return c.write(msg)
+}
+
+func (c *conn) notify(method string, params any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	return c.write(&message{
+		Method: method,
+		Params: raw,
+	})
+}
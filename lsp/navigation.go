@@ -0,0 +1,116 @@
+package lsp
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/code-game-project/cge-parser/parser"
+	"github.com/code-game-project/cge-parser/query"
+)
+
+func (s *Server) hover(params TextDocumentPositionParams) (*Hover, error) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	info, ok := doc.analysis.Hover(params.Position.parserPos())
+	if !ok {
+		return nil, nil
+	}
+
+	value := "```cge\n" + info.Signature + "\n```"
+	if info.Doc != "" {
+		value += "\n\n" + info.Doc
+	}
+
+	r := doc.tokenRange(info.Token)
+	return &Hover{
+		Contents: MarkupContent{
+			Kind:  "markdown",
+			Value: value,
+		},
+		Range: &r,
+	}, nil
+}
+
+func (s *Server) definition(params TextDocumentPositionParams) ([]Location, error) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	token, ok := doc.analysis.Definition(params.Position.parserPos())
+	if !ok {
+		return nil, nil
+	}
+	return []Location{doc.location(token)}, nil
+}
+
+func (s *Server) references(params ReferenceParams) ([]Location, error) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	tokens, ok := doc.analysis.Occurrences(params.Position.parserPos())
+	if !ok {
+		return nil, nil
+	}
+
+	locations := make([]Location, 0, len(tokens))
+	for i, token := range tokens {
+		if i == 0 && !params.Context.IncludeDeclaration {
+			continue
+		}
+		locations = append(locations, doc.location(token))
+	}
+	return locations, nil
+}
+
+func (s *Server) prepareRename(params TextDocumentPositionParams) (*Range, error) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	t, ok := doc.analysis.TargetAt(params.Position.parserPos())
+	if !ok || !isRenamable(t) {
+		return nil, nil
+	}
+	r := doc.tokenRange(t.Token)
+	return &r, nil
+}
+
+var identifierRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+func (s *Server) rename(params RenameParams) (*WorkspaceEdit, error) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+	t, ok := doc.analysis.TargetAt(params.Position.parserPos())
+	if !ok || !isRenamable(t) {
+		return nil, fmt.Errorf("the element at this position cannot be renamed")
+	}
+	if !identifierRegex.MatchString(params.NewName) || parser.IsKeyword(params.NewName) {
+		return nil, fmt.Errorf("'%s' is not a valid identifier", params.NewName)
+	}
+
+	tokens, _ := doc.analysis.Occurrences(t.Token.Line, t.Token.Column)
+	edits := make([]TextEdit, 0, len(tokens))
+	for _, token := range tokens {
+		edits = append(edits, TextEdit{
+			Range:   doc.tokenRange(token),
+			NewText: params.NewName,
+		})
+	}
+	return &WorkspaceEdit{
+		Changes: map[string][]TextEdit{
+			doc.uri: edits,
+		},
+	}, nil
+}
+
+func isRenamable(t query.Target) bool {
+	if t.Kind == query.TargetDeclaration || t.Kind == query.TargetReference {
+		return t.Object != nil && t.Object.Type != parser.TTConfig
+	}
+	return true
+}
@@ -0,0 +1,279 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/code-game-project/cge-parser/cge"
+	"github.com/code-game-project/cge-parser/parser"
+)
+
+type Server struct {
+	conn      *conn
+	documents map[string]*document
+	shutdown  bool
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		conn:      newConn(in, out),
+		documents: make(map[string]*document),
+	}
+}
+
+// Run handles LSP messages until the client sends the exit notification or closes the input.
+func (s *Server) Run() error {
+	for {
+		msg, err := s.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			var rerr *responseError
+			if errors.As(err, &rerr) {
+				s.conn.reply(nil, nil, rerr)
+				continue
+			}
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("received exit notification before shutdown request")
+			}
+			return nil
+		}
+
+		if msg.ID == nil {
+			err = s.handleNotification(msg.Method, msg.Params)
+			if err != nil {
+				s.conn.notify("window/logMessage", map[string]any{
+					"type":    1,
+					"message": fmt.Sprintf("%s: %s", msg.Method, err),
+				})
+			}
+			continue
+		}
+
+		result, err := s.handleRequest(msg.Method, msg.Params)
+		err = s.conn.reply(msg.ID, result, err)
+		if err != nil {
+			return fmt.Errorf("failed to send response: %w", err)
+		}
+	}
+}
+
+func (s *Server) handleNotification(method string, params json.RawMessage) error {
+	switch method {
+	case "textDocument/didOpen":
+		var p DidOpenTextDocumentParams
+		if err := decode(params, &p); err != nil {
+			return err
+		}
+		doc, err := newDocument(p.TextDocument.URI, p.TextDocument.Version, p.TextDocument.Text)
+		if err != nil {
+			return err
+		}
+		s.documents[doc.uri] = doc
+		return s.publishDiagnostics(doc)
+	case "textDocument/didChange":
+		var p DidChangeTextDocumentParams
+		if err := decode(params, &p); err != nil {
+			return err
+		}
+		doc, ok := s.documents[p.TextDocument.URI]
+		if !ok {
+			return nil
+		}
+		for _, change := range p.ContentChanges {
+			var err error
+			if change.Range == nil {
+				err = doc.replace(p.TextDocument.Version, change.Text)
+			} else {
+				err = doc.edit(p.TextDocument.Version, *change.Range, change.Text)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return s.publishDiagnostics(doc)
+	case "textDocument/didClose":
+		var p DidCloseTextDocumentParams
+		if err := decode(params, &p); err != nil {
+			return err
+		}
+		delete(s.documents, p.TextDocument.URI)
+		return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         p.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+	}
+	return nil
+}
+
+func (s *Server) handleRequest(method string, params json.RawMessage) (any, error) {
+	if s.shutdown {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shutting down"}
+	}
+
+	switch method {
+	case "initialize":
+		return s.initialize()
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/hover":
+		var p TextDocumentPositionParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.hover(p)
+	case "textDocument/definition":
+		var p TextDocumentPositionParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.definition(p)
+	case "textDocument/references":
+		var p ReferenceParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.references(p)
+	case "textDocument/completion":
+		var p TextDocumentPositionParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.completion(p)
+	case "textDocument/documentSymbol":
+		var p struct {
+			TextDocument TextDocumentIdentifier `json:"textDocument"`
+		}
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.documentSymbols(p.TextDocument)
+	case "textDocument/semanticTokens/full":
+		var p struct {
+			TextDocument TextDocumentIdentifier `json:"textDocument"`
+		}
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.semanticTokens(p.TextDocument)
+	case "textDocument/prepareRename":
+		var p TextDocumentPositionParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.prepareRename(p)
+	case "textDocument/rename":
+		var p RenameParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.rename(p)
+	case "textDocument/formatting":
+		var p DocumentFormattingParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.formatting(p)
+	case "textDocument/codeAction":
+		var p CodeActionParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		return s.codeActions(p)
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method '%s' not supported", method)}
+}
+
+func (s *Server) initialize() (*InitializeResult, error) {
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			PositionEncoding:   "utf-16",
+			TextDocumentSync:   2,
+			HoverProvider:      true,
+			DefinitionProvider: true,
+			ReferencesProvider: true,
+			CompletionProvider: CompletionOptions{
+				TriggerCharacters: []string{":", "<"},
+			},
+			DocumentSymbolProvider: true,
+			SemanticTokensProvider: SemanticTokensOptions{
+				Legend: SemanticTokensLegend{
+					TokenTypes:     semanticTokenTypes,
+					TokenModifiers: semanticTokenModifiers,
+				},
+				Full: true,
+			},
+			RenameProvider: RenameOptions{
+				PrepareProvider: true,
+			},
+			DocumentFormattingProvider: true,
+			CodeActionProvider:         true,
+		},
+		ServerInfo: ServerInfo{
+			Name:    "cge-parser",
+			Version: cge.CGEVersion,
+		},
+	}, nil
+}
+
+func (s *Server) publishDiagnostics(doc *document) error {
+	diagnostics := make([]Diagnostic, 0, len(doc.analysis.diagnostics))
+	for _, d := range doc.analysis.diagnostics {
+		diagnostics = append(diagnostics, doc.lspDiagnostic(d))
+	}
+	version := doc.version
+	return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     &version,
+		Diagnostics: diagnostics,
+	})
+}
+
+func (s *Server) formatting(params DocumentFormattingParams) ([]TextEdit, error) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil
+	}
+
+	indent := "\t"
+	if params.Options.InsertSpaces {
+		indent = strings.Repeat(" ", params.Options.TabSize)
+	}
+
+	var formatted strings.Builder
+	err := parser.Format(strings.NewReader(doc.text), &formatted, indent)
+	if err != nil {
+		return nil, err
+	}
+	if formatted.String() == doc.text {
+		return []TextEdit{}, nil
+	}
+
+	lastLine := strings.Count(doc.text, "\n")
+	return []TextEdit{
+		{
+			Range: Range{
+				Start: Position{},
+				End:   lspPos(lastLine, doc.tree.LineEnd(lastLine)),
+			},
+			NewText: formatted.String(),
+		},
+	}, nil
+}
+
+func decode(params json.RawMessage, v any) error {
+	err := json.Unmarshal(params, v)
+	if err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
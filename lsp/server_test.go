@@ -0,0 +1,369 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/code-game-project/cge-parser/parser"
+)
+
+// client talks to a Server over in-memory pipes.
+type client struct {
+	t        *testing.T
+	out      io.WriteCloser
+	conn     *conn
+	messages chan *message
+	done     chan error
+	nextID   int
+}
+
+func newClient(t *testing.T) *client {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	c := &client{
+		t:        t,
+		out:      clientOut,
+		conn:     newConn(nil, clientOut),
+		messages: make(chan *message, 16),
+		done:     make(chan error, 1),
+	}
+
+	server := NewServer(serverIn, serverOut)
+	go func() {
+		c.done <- server.Run()
+		serverOut.Close()
+	}()
+	go func() {
+		in := newConn(clientIn, nil)
+		for {
+			msg, err := in.read()
+			if err != nil {
+				close(c.messages)
+				return
+			}
+			c.messages <- msg
+		}
+	}()
+	t.Cleanup(func() {
+		clientOut.Close()
+	})
+	return c
+}
+
+func (c *client) receive() *message {
+	c.t.Helper()
+	select {
+	case msg, ok := <-c.messages:
+		if !ok {
+			c.t.Fatal("server closed the connection")
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("timed out waiting for a message")
+	}
+	return nil
+}
+
+func (c *client) notify(method string, params any) {
+	c.t.Helper()
+	raw, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	err = c.conn.write(&message{Method: method, Params: raw})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// request sends a request and decodes the result of the response into result.
+func (c *client) request(method string, params any, result any) *responseError {
+	c.t.Helper()
+	c.nextID++
+	id := json.RawMessage(fmt.Sprint(c.nextID))
+	raw, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	err = c.conn.write(&message{ID: &id, Method: method, Params: raw})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	msg := c.receive()
+	if msg.ID == nil || string(*msg.ID) != string(id) {
+		c.t.Fatalf("expected response to request %s, got %+v", id, msg)
+	}
+	if msg.Error != nil {
+		return msg.Error
+	}
+	if result != nil {
+		decodeResult(c.t, msg.Result, result)
+	}
+	return nil
+}
+
+// diagnostics waits for the next publishDiagnostics notification.
+func (c *client) diagnostics() PublishDiagnosticsParams {
+	c.t.Helper()
+	msg := c.receive()
+	if msg.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("expected publishDiagnostics notification, got %+v", msg)
+	}
+	var params PublishDiagnosticsParams
+	decodeResult(c.t, msg.Params, &params)
+	return params
+}
+
+func (c *client) open(uri, text string) PublishDiagnosticsParams {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "cge", Version: 1, Text: text},
+	})
+	return c.diagnostics()
+}
+
+func decodeResult(t *testing.T, value any, v any) {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", data, err)
+	}
+}
+
+func rng(startLine, startCharacter, endLine, endCharacter int) Range {
+	return Range{Start: Position{startLine, startCharacter}, End: Position{endLine, endCharacter}}
+}
+
+func TestInitialize(t *testing.T) {
+	c := newClient(t)
+	var result InitializeResult
+	if err := c.request("initialize", map[string]any{"capabilities": map[string]any{}}, &result); err != nil {
+		t.Fatalf("initialize error = %v", err)
+	}
+	if result.Capabilities.PositionEncoding != "utf-16" || result.Capabilities.TextDocumentSync != 2 || !result.Capabilities.HoverProvider {
+		t.Errorf("capabilities = %+v", result.Capabilities)
+	}
+	if result.ServerInfo.Name != "cge-parser" {
+		t.Errorf("server name = %q, want cge-parser", result.ServerInfo.Name)
+	}
+
+	if err := c.request("shutdown", nil, nil); err != nil {
+		t.Fatalf("shutdown error = %v", err)
+	}
+	if err := c.request("textDocument/hover", TextDocumentPositionParams{}, nil); err == nil || err.Code != codeInvalidRequest {
+		t.Errorf("request after shutdown error = %v, want invalid request", err)
+	}
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///game.cge"
+
+	// the emoji takes two UTF-16 code units
+	got := c.open(uri, "cge 0.5\nevent a { /* 😀 */ x: strin }\n")
+	if got.URI != uri || got.Version == nil || *got.Version != 1 || len(got.Diagnostics) != 1 {
+		t.Fatalf("publishDiagnostics = %+v", got)
+	}
+	d := got.Diagnostics[0]
+	if d.Range != rng(1, 22, 1, 27) || d.Severity != SeverityError || d.Code != parser.CodeUndefinedType.String() {
+		t.Errorf("diagnostic = %+v, want undefined type at 1:22-1:27", d)
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Range: &Range{Start: Position{1, 27}, End: Position{1, 27}}, Text: "g"}},
+	})
+	got = c.diagnostics()
+	if *got.Version != 2 || len(got.Diagnostics) != 0 {
+		t.Errorf("publishDiagnostics after incremental change = %+v, want no diagnostics", got)
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 3},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "cge 0.5\nevent a { x: foo }\n"}},
+	})
+	got = c.diagnostics()
+	if len(got.Diagnostics) != 1 || got.Diagnostics[0].Range != rng(1, 13, 1, 16) {
+		t.Errorf("publishDiagnostics after full change = %+v, want undefined type at 1:13-1:16", got)
+	}
+
+	c.notify("textDocument/didClose", DidCloseTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: uri}})
+	if got = c.diagnostics(); len(got.Diagnostics) != 0 {
+		t.Errorf("publishDiagnostics after close = %+v, want no diagnostics", got)
+	}
+}
+
+const navigationSource = "cge 0.5\n\n// A player.\ntype player {\n\tusername: string,\n}\n\nevent joined { /* 😀 */ player: player }\n"
+
+func TestNavigation(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///game.cge"
+	c.open(uri, navigationSource)
+	// the type of the property 'player' in UTF-16 code units
+	position := TextDocumentPositionParams{TextDocument: TextDocumentIdentifier{URI: uri}, Position: Position{7, 33}}
+
+	var hover Hover
+	if err := c.request("textDocument/hover", position, &hover); err != nil {
+		t.Fatalf("hover error = %v", err)
+	}
+	if !strings.Contains(hover.Contents.Value, "type player") || !strings.Contains(hover.Contents.Value, "A player.") {
+		t.Errorf("hover contents = %q", hover.Contents.Value)
+	}
+	if hover.Range == nil || *hover.Range != rng(7, 32, 7, 38) {
+		t.Errorf("hover range = %+v, want 7:32-7:38", hover.Range)
+	}
+
+	var definition []Location
+	if err := c.request("textDocument/definition", position, &definition); err != nil {
+		t.Fatalf("definition error = %v", err)
+	}
+	if want := []Location{{URI: uri, Range: rng(3, 5, 3, 11)}}; !reflect.DeepEqual(definition, want) {
+		t.Errorf("definition = %+v, want %+v", definition, want)
+	}
+
+	var edit WorkspaceEdit
+	if err := c.request("textDocument/rename", RenameParams{TextDocumentPositionParams: position, NewName: "user"}, &edit); err != nil {
+		t.Fatalf("rename error = %v", err)
+	}
+	want := []TextEdit{{Range: rng(3, 5, 3, 11), NewText: "user"}, {Range: rng(7, 32, 7, 38), NewText: "user"}}
+	if !reflect.DeepEqual(edit.Changes[uri], want) {
+		t.Errorf("rename edits = %+v, want %+v", edit.Changes[uri], want)
+	}
+	if err := c.request("textDocument/rename", RenameParams{TextDocumentPositionParams: position, NewName: "event"}, &edit); err == nil {
+		t.Errorf("rename to keyword: expected error")
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///game.cge"
+	c.open(uri, "cge 0.5\nevent a {x: int32,y:   string}\n")
+
+	tests := []struct {
+		name         string
+		insertSpaces bool
+		want         string
+	}{
+		{"spaces", true, "cge 0.5\nevent a {\n  x: int32,\n  y: string\n}\n"},
+		{"tabs", false, "cge 0.5\nevent a {\n\tx: int32,\n\ty: string\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var edits []TextEdit
+			params := DocumentFormattingParams{TextDocument: TextDocumentIdentifier{URI: uri}}
+			params.Options.TabSize = 2
+			params.Options.InsertSpaces = tt.insertSpaces
+			if err := c.request("textDocument/formatting", params, &edits); err != nil {
+				t.Fatalf("formatting error = %v", err)
+			}
+			want := []TextEdit{{Range: rng(0, 0, 2, 0), NewText: tt.want}}
+			if !reflect.DeepEqual(edits, want) {
+				t.Errorf("formatting edits = %+v, want %+v", edits, want)
+			}
+		})
+	}
+}
+
+func TestMalformedMessages(t *testing.T) {
+	c := newClient(t)
+
+	if err := c.request("textDocument/unknown", nil, nil); err == nil || err.Code != codeMethodNotFound {
+		t.Errorf("unknown method error = %v, want method not found", err)
+	}
+	// unknown notifications are ignored
+	c.notify("$/unknown", nil)
+
+	fmt.Fprint(c.out, "Content-Length: 5\r\n\r\n{abc}")
+	msg := c.receive()
+	if msg.Error == nil || msg.Error.Code != codeParseError {
+		t.Errorf("response to invalid JSON = %+v, want parse error", msg)
+	}
+	if err := c.request("initialize", nil, &InitializeResult{}); err != nil {
+		t.Errorf("initialize after invalid JSON error = %v", err)
+	}
+
+	fmt.Fprint(c.out, "Content-Length: -1\r\n\r\n")
+	select {
+	case err := <-c.done:
+		if err == nil || !strings.Contains(err.Error(), "Content-Length") {
+			t.Errorf("Run() error = %v, want invalid Content-Length", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after invalid Content-Length")
+	}
+}
+
+func TestReplyID(t *testing.T) {
+	var out strings.Builder
+	err := newConn(nil, &out).reply(nil, nil, &responseError{Code: codeParseError, Message: "invalid JSON"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"invalid JSON"}}`
+	if _, body, _ := strings.Cut(out.String(), "\r\n\r\n"); body != want {
+		t.Errorf("reply() = %s, want %s", body, want)
+	}
+}
+
+func TestContentLength(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing", "Content-Type: application/json\r\n\r\n{}"},
+		{"not a number", "Content-Length: abc\r\n\r\n{}"},
+		{"negative", "Content-Length: -5\r\n\r\n{}"},
+		{"longer than the input", "Content-Length: 1000000000000\r\n\r\n{}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newConn(strings.NewReader(tt.input), nil).read()
+			if err == nil {
+				t.Errorf("read() expected error")
+			}
+		})
+	}
+}
+
+// symbolSummary describes the symbols as "name line:character-line:character" with their children in braces.
+func symbolSummary(symbols []DocumentSymbol) string {
+	parts := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		part := fmt.Sprintf("%s %d:%d-%d:%d", s.Name, s.Range.Start.Line, s.Range.Start.Character, s.Range.End.Line, s.Range.End.Character)
+		if len(s.Children) > 0 {
+			part += " {" + symbolSummary(s.Children) + "}"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///game.cge"
+	c.open(uri, "cge 0.5\nevent joined {\n\tpositions: list<type pos { x: type inner { y: int32 } }>,\n\t/* 😀 */ home: type base { team: int32 },\n}\nenum team { red }\n")
+
+	var symbols []DocumentSymbol
+	if err := c.request("textDocument/documentSymbol", map[string]any{"textDocument": TextDocumentIdentifier{URI: uri}}, &symbols); err != nil {
+		t.Fatalf("documentSymbol error = %v", err)
+	}
+	// the inline declarations inside generics are nested, ranges are in UTF-16 code units
+	want := "joined 1:0-4:1 {positions 2:1-2:57 {pos 2:17-2:56 {x 2:28-2:54 {inner 2:31-2:54 {y 2:44-2:52}}}}, home 3:10-3:41 {base 3:16-3:41 {team 3:28-3:39}}}, team 5:0-5:17 {red 5:12-5:15}"
+	if got := symbolSummary(symbols); got != want {
+		t.Errorf("document symbols =\n%s\nwant\n%s", got, want)
+	}
+}
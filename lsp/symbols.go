@@ -0,0 +1,227 @@
+package lsp
+
+import (
+	"github.com/code-game-project/cge-parser/parser"
+	"github.com/code-game-project/cge-parser/query"
+)
+
+func (s *Server) documentSymbols(params TextDocumentIdentifier) ([]DocumentSymbol, error) {
+	doc, ok := s.documents[params.URI]
+	if !ok {
+		return nil, nil
+	}
+
+	objects := doc.analysis.Objects()
+	named := make([]*parser.Object, 0, len(objects))
+	for i := range objects {
+		if objects[i].Name.Lexeme != "" {
+			named = append(named, &objects[i])
+		}
+	}
+	// inline declarations are nested as children of the property whose type contains them
+	inside := func(span parser.Span) []*parser.Object {
+		var contained []*parser.Object
+		for _, o := range named {
+			if containsSpan(span, o.Span) {
+				contained = append(contained, o)
+			}
+		}
+		nested := make([]*parser.Object, 0, len(contained))
+		for _, o := range contained {
+			if !isInline(contained, o) {
+				nested = append(nested, o)
+			}
+		}
+		return nested
+	}
+
+	var objectSymbol func(o *parser.Object) DocumentSymbol
+	objectSymbol = func(o *parser.Object) DocumentSymbol {
+		kind := SymbolKindStruct
+		switch o.Type {
+		case parser.TTConfig:
+			kind = SymbolKindModule
+		case parser.TTCommand:
+			kind = SymbolKindFunction
+		case parser.TTEvent:
+			kind = SymbolKindEvent
+		case parser.TTEnum:
+			kind = SymbolKindEnum
+		}
+		symbol := DocumentSymbol{
+			Name:           o.Name.Lexeme,
+			Detail:         o.Keyword(),
+			Kind:           kind,
+			Range:          spanRange(o.Span),
+			SelectionRange: doc.tokenRange(o.Name),
+			Children:       make([]DocumentSymbol, 0, len(o.Properties)),
+		}
+		for _, p := range o.Properties {
+			child := DocumentSymbol{
+				Name:           p.Name,
+				Kind:           SymbolKindField,
+				Range:          spanRange(p.Span),
+				SelectionRange: doc.tokenRange(p.NameToken),
+			}
+			if o.Type == parser.TTEnum {
+				child.Kind = SymbolKindEnumMember
+			} else if p.Type != nil {
+				child.Detail = query.TypeString(p.Type)
+				for _, inline := range inside(p.Type.Span) {
+					child.Children = append(child.Children, objectSymbol(inline))
+				}
+			}
+			symbol.Children = append(symbol.Children, child)
+		}
+		return symbol
+	}
+
+	symbols := make([]DocumentSymbol, 0, len(named))
+	for _, o := range named {
+		if !isInline(named, o) {
+			symbols = append(symbols, objectSymbol(o))
+		}
+	}
+	return symbols, nil
+}
+
+// isInline reports whether the object is declared inside of another one of the objects.
+func isInline(objects []*parser.Object, o *parser.Object) bool {
+	for _, outer := range objects {
+		if outer != o && containsSpan(outer.Span, o.Span) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsSpan reports whether inner lies within outer.
+func containsSpan(outer, inner parser.Span) bool {
+	startsAfter := inner.StartLine > outer.StartLine || inner.StartLine == outer.StartLine && inner.StartColumn >= outer.StartColumn
+	endsBefore := inner.EndLine < outer.EndLine || inner.EndLine == outer.EndLine && inner.EndColumn <= outer.EndColumn
+	return startsAfter && endsBefore
+}
+
+func spanRange(span parser.Span) Range {
+	return Range{
+		Start: lspPos(span.StartLine, span.StartColumn),
+		End:   lspPos(span.EndLine, span.EndColumn),
+	}
+}
+
+var semanticTokenTypes = []string{"keyword", "type", "struct", "enum", "enumMember", "event", "function", "property", "comment", "number", "namespace"}
+
+var semanticTokenModifiers = []string{"declaration", "deprecated"}
+
+const (
+	semKeyword uint32 = iota
+	semType
+	semStruct
+	semEnum
+	semEnumMember
+	semEvent
+	semFunction
+	semProperty
+	semComment
+	semNumber
+	semNamespace
+)
+
+const (
+	modDeclaration uint32 = 1 << iota
+	modDeprecated
+)
+
+func (s *Server) semanticTokens(params TextDocumentIdentifier) (*SemanticTokens, error) {
+	doc, ok := s.documents[params.URI]
+	if !ok {
+		return nil, nil
+	}
+	a := doc.analysis
+
+	classes := a.classifyIdentifiers()
+
+	data := make([]uint32, 0, len(a.tokens)*5)
+	prevLine, prevChar := 0, 0
+	emit := func(line, start, end int, tokenType, modifiers uint32) {
+		deltaChar := start
+		if line == prevLine {
+			deltaChar -= prevChar
+		}
+		data = append(data, uint32(line-prevLine), uint32(deltaChar), uint32(end-start), tokenType, modifiers)
+		prevLine, prevChar = line, start
+	}
+
+	for _, t := range a.tokens {
+		var tokenType, modifiers uint32
+		switch t.Type {
+		case parser.TTGameName:
+			tokenType, modifiers = semKeyword, modDeprecated
+		case parser.TTCGEVersion, parser.TTConfig, parser.TTCommand, parser.TTEvent, parser.TTType, parser.TTEnum:
+			tokenType = semKeyword
+		case parser.TTString, parser.TTBool, parser.TTInt32, parser.TTInt64, parser.TTFloat32, parser.TTFloat64, parser.TTMap, parser.TTList:
+			tokenType = semType
+		case parser.TTVersionNumber:
+			tokenType = semNumber
+		case parser.TTComment:
+			// multi-line tokens are split into one token per line
+			for line := t.Line; line <= t.EndLine; line++ {
+				start, end := 0, t.EndColumn
+				if line == t.Line {
+					start = t.Column
+				}
+				if line < t.EndLine {
+					end = doc.tree.LineEnd(line)
+				}
+				emit(line, start, end, semComment, 0)
+			}
+			continue
+		case parser.TTIdentifier:
+			class, ok := classes[[2]int{t.Line, t.Column}]
+			if !ok {
+				continue
+			}
+			tokenType, modifiers = class[0], class[1]
+		default:
+			continue
+		}
+		emit(t.Line, t.Column, t.EndColumn, tokenType, modifiers)
+	}
+
+	return &SemanticTokens{
+		Data: data,
+	}, nil
+}
+
+func (a *analysis) classifyIdentifiers() map[[2]int][2]uint32 {
+	classes := make(map[[2]int][2]uint32, len(a.semanticTokens))
+	for _, t := range a.semanticTokens {
+		var class, modifiers uint32
+		switch t.Kind {
+		case parser.SemanticGameName:
+			class = semNamespace
+		case parser.SemanticCommand:
+			class = semFunction
+		case parser.SemanticEvent:
+			class = semEvent
+		case parser.SemanticType:
+			class = semStruct
+		case parser.SemanticEnum:
+			class = semEnum
+		case parser.SemanticEnumValue:
+			class = semEnumMember
+		case parser.SemanticProperty:
+			class = semProperty
+		default:
+			class = semType
+		}
+		if t.Role == parser.SemanticDeclaration {
+			modifiers |= modDeclaration
+		}
+		if t.Deprecated {
+			modifiers |= modDeprecated
+		}
+		classes[[2]int{t.Token.Line, t.Token.Column}] = [2]uint32{class, modifiers}
+	}
+	return classes
+}
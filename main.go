@@ -1,34 +1,268 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/pflag"
 
+	"github.com/code-game-project/cge-parser/diff"
+	"github.com/code-game-project/cge-parser/lsp"
 	"github.com/code-game-project/cge-parser/parser"
 	"github.com/code-game-project/cge-parser/protobuf"
+	"github.com/code-game-project/cge-parser/query"
 )
 
 func run() error {
 	comments := pflag.Bool("comments", false, "include doc comments in output")
 	onlyMeta := pflag.Bool("only-meta", false, "stop parsing after sending the metadata message")
 	tokens := pflag.Bool("tokens", false, "return all parsed tokens")
+	semanticTokens := pflag.Bool("semantic-tokens", false, "return the semantic classification of all identifiers")
 	noObjects := pflag.Bool("no-objects", false, "do not return objects")
 	noWarn := pflag.Bool("no-warn", false, "disable warnings")
+	errorTolerant := pflag.Bool("error-tolerant", false, "return all objects even if the file contains errors")
+	bestEffort := pflag.Bool("best-effort", false, "continue parsing files with an incompatible CGE version")
+	assumeVersion := pflag.Bool("assume-version", false, "send the CGE version of the parser as the metadata if the file does not contain a valid version")
+	lintConfigFile := pflag.String("lint-config", "", "read lint rule severities from a JSON `file`")
+	lintRules := pflag.StringArray("lint", nil, "set the severity of a lint rule (`rule=off|info|warning|error`), overrides --lint-config")
+	maxBytes := pflag.Int("max-bytes", 0, "maximum size of the input in bytes (0: unlimited)")
+	maxTokens := pflag.Int("max-tokens", 0, "maximum number of tokens (0: unlimited)")
+	maxNestingDepth := pflag.Int("max-nesting-depth", 0, "maximum nesting depth of property types (0: 256)")
+	maxDeclarations := pflag.Int("max-declarations", 0, "maximum number of declarations (0: unlimited)")
+	maxIdentifierLength := pflag.Int("max-identifier-length", 0, "maximum length of identifiers (0: unlimited)")
+	positionEncoding := pflag.String("position-encoding", "utf-32", "count columns in code points (`utf-32`), UTF-16 code units (utf-16) or bytes (utf-8)")
+	oneBased := pflag.Bool("one-based", false, "start lines and columns at 1 instead of 0")
+	maxErrors := pflag.Int("max-errors", 0, "report at most `N` errors (0: unlimited)")
 	pflag.Parse()
 
-	return parser.Parse(os.Stdin, protobuf.NewSender(os.Stdout), parser.Config{
-		IncludeComments: *comments,
-		OnlyMetadata:    *onlyMeta,
-		SendTokens:      *tokens,
-		NoObjects:       *noObjects,
-		DisableWarnings: *noWarn,
+	lint, err := lintConfig(*lintConfigFile, *lintRules)
+	if err != nil {
+		return err
+	}
+
+	encoding, ok := parser.ParsePositionEncoding(*positionEncoding)
+	if !ok {
+		return fmt.Errorf("invalid position encoding '%s': expected utf-32, utf-16 or utf-8", *positionEncoding)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return parser.ParseContext(ctx, os.Stdin, protobuf.NewSender(os.Stdout), parser.Config{
+		IncludeComments:    *comments,
+		OnlyMetadata:       *onlyMeta,
+		SendTokens:         *tokens,
+		SendSemanticTokens: *semanticTokens,
+		NoObjects:          *noObjects,
+		DisableWarnings:    *noWarn,
+		BestEffort:         *bestEffort,
+		AssumeVersion:      *assumeVersion,
+		ErrorTolerant:      *errorTolerant,
+		Lint:               lint,
+		Limits: parser.Limits{
+			MaxBytes:            *maxBytes,
+			MaxTokens:           *maxTokens,
+			MaxNestingDepth:     *maxNestingDepth,
+			MaxDeclarations:     *maxDeclarations,
+			MaxIdentifierLength: *maxIdentifierLength,
+		},
+		PositionEncoding: encoding,
+		OneBased:         *oneBased,
+		MaxErrors:        *maxErrors,
 	})
 }
 
+func lintConfig(file string, rules []string) (parser.LintConfig, error) {
+	var config parser.LintConfig
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return parser.LintConfig{}, fmt.Errorf("failed to open lint config: %w", err)
+		}
+		defer f.Close()
+		config, err = parser.ParseLintConfig(f)
+		if err != nil {
+			return parser.LintConfig{}, err
+		}
+	}
+	for _, r := range rules {
+		rule, severity, ok := strings.Cut(r, "=")
+		if !ok {
+			return parser.LintConfig{}, fmt.Errorf("invalid lint rule '%s': expected rule=severity", r)
+		}
+		err := config.Set(rule, severity)
+		if err != nil {
+			return parser.LintConfig{}, err
+		}
+	}
+	return config, nil
+}
+
+// runQuery answers a query about the CGE file read from in. Exactly one message is sent to out,
+// which is empty if there is no symbol at the position.
+func runQuery(args []string, in io.Reader, out io.Writer) error {
+	flags := pflag.NewFlagSet("query", pflag.ContinueOnError)
+	definition := flags.String("definition", "", "return the declaration of the symbol at LINE:COLUMN")
+	references := flags.String("references", "", "return the declaration and all references of the symbol at LINE:COLUMN")
+	hover := flags.String("hover", "", "return the signature and doc comment of the symbol at LINE:COLUMN")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	var position string
+	count := 0
+	for _, p := range []string{*definition, *references, *hover} {
+		if p != "" {
+			position = p
+			count++
+		}
+	}
+	if count != 1 {
+		return errors.New("exactly one of --definition, --references or --hover is required")
+	}
+	line, column, err := parsePosition(position)
+	if err != nil {
+		return err
+	}
+
+	tree, err := parser.ParseTree(in, parser.Config{
+		IncludeComments: true,
+	})
+	if err != nil {
+		return err
+	}
+	index := query.NewIndex(tree.Objects())
+	sender := protobuf.NewSender(out)
+
+	switch {
+	case *definition != "":
+		if token, ok := index.Definition(line, column); ok {
+			return sender.SendDefinition(&token)
+		}
+		return sender.SendDefinition(nil)
+	case *references != "":
+		tokens, _ := index.Occurrences(line, column)
+		return sender.SendReferences(tokens)
+	default:
+		if info, ok := index.Hover(line, column); ok {
+			return sender.SendHover(&info)
+		}
+		return sender.SendHover(nil)
+	}
+}
+
+// parsePosition parses a zero-based LINE:COLUMN position.
+func parsePosition(position string) (line, column int, err error) {
+	l, c, ok := strings.Cut(position, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid position '%s': expected LINE:COLUMN", position)
+	}
+	line, err = strconv.Atoi(l)
+	if err != nil || line < 0 {
+		return 0, 0, fmt.Errorf("invalid line in position '%s'", position)
+	}
+	column, err = strconv.Atoi(c)
+	if err != nil || column < 0 {
+		return 0, 0, fmt.Errorf("invalid column in position '%s'", position)
+	}
+	return line, column, nil
+}
+
+func runFix(args []string) error {
+	flags := pflag.NewFlagSet("fix", pflag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print a unified diff instead of writing the files")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		source, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		fixed, _, err := parser.FixSource(string(source))
+		if err != nil {
+			return err
+		}
+		if *dryRun {
+			fmt.Print(diff.Unified("a/stdin", "b/stdin", string(source), fixed))
+			return nil
+		}
+		fmt.Print(fixed)
+		return nil
+	}
+
+	for _, name := range flags.Args() {
+		source, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read '%s': %w", name, err)
+		}
+		fixed, count, err := parser.FixSource(string(source))
+		if err != nil {
+			return fmt.Errorf("failed to fix '%s': %w", name, err)
+		}
+		if count == 0 {
+			continue
+		}
+		if *dryRun {
+			fmt.Print(diff.Unified("a/"+strings.TrimPrefix(name, "/"), "b/"+strings.TrimPrefix(name, "/"), string(source), fixed))
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(name, []byte(fixed), info.Mode().Perm())
+		if err != nil {
+			return fmt.Errorf("failed to write '%s': %w", name, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s: applied %d fixes\n", name, count)
+	}
+	return nil
+}
+
+func runExplain(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		for _, c := range parser.DiagnosticCodes() {
+			fmt.Fprintf(out, "%s %-26s %s\n", c, c.Name(), c.Category())
+		}
+		return nil
+	}
+	for i, a := range args {
+		code, ok := parser.ParseDiagnosticCode(a)
+		if !ok {
+			return fmt.Errorf("unknown diagnostic code '%s'", a)
+		}
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprint(out, code.Explain())
+	}
+	return nil
+}
+
 func main() {
-	if err := run(); err != nil {
+	var err error
+	if len(os.Args) > 1 && os.Args[1] == "lsp" {
+		err = lsp.NewServer(os.Stdin, os.Stdout).Run()
+	} else if len(os.Args) > 1 && os.Args[1] == "query" {
+		err = runQuery(os.Args[2:], os.Stdin, os.Stdout)
+	} else if len(os.Args) > 1 && os.Args[1] == "fix" {
+		err = runFix(os.Args[2:])
+	} else if len(os.Args) > 1 && os.Args[1] == "explain" {
+		err = runExplain(os.Args[2:], os.Stdout)
+	} else {
+		err = run()
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/code-game-project/cge-parser/parser"
+	"github.com/code-game-project/cge-parser/protobuf/schema"
+)
+
+func TestRunExplain(t *testing.T) {
+	var out strings.Builder
+	if err := runExplain([]string{"undefined-type", "cge22"}, &out); err != nil {
+		t.Fatalf("runExplain() error = %v", err)
+	}
+	want := parser.CodeUndefinedType.Explain() + "\n" + parser.CodeTypeAlias.Explain()
+	if out.String() != want {
+		t.Errorf("runExplain() = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := runExplain(nil, &out); err != nil {
+		t.Fatalf("runExplain() error = %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != len(parser.DiagnosticCodes()) {
+		t.Errorf("runExplain() listed %d codes, want %d", lines, len(parser.DiagnosticCodes()))
+	}
+
+	if err := runExplain([]string{"CGE9999"}, &out); err == nil {
+		t.Errorf("runExplain() with unknown code: expected error")
+	}
+}
+
+func TestRunQuery(t *testing.T) {
+	source := "cge 0.5\ntype player { name: string }\nevent joined { player: player }\n"
+	tests := []struct {
+		flag      string
+		position  string
+		wantType  schema.MsgType_Type
+		wantEmpty bool
+	}{
+		{"--definition", "2:24", schema.MsgType_DEFINITION, false},
+		{"--definition", "0:0", schema.MsgType_DEFINITION, true},
+		{"--references", "1:6", schema.MsgType_REFERENCES, false},
+		{"--references", "0:0", schema.MsgType_REFERENCES, true},
+		{"--hover", "1:6", schema.MsgType_HOVER, false},
+		{"--hover", "0:0", schema.MsgType_HOVER, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flag+" "+tt.position, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := runQuery([]string{tt.flag, tt.position}, strings.NewReader(source), &out); err != nil {
+				t.Fatalf("runQuery() error = %v", err)
+			}
+			in := bufio.NewReader(&out)
+			msgType := new(schema.MsgType)
+			if err := protodelim.UnmarshalFrom(in, msgType); err != nil || msgType.Type != tt.wantType {
+				t.Fatalf("message type = %v, %v, want %v", msgType.Type, err, tt.wantType)
+			}
+			var msg proto.Message
+			switch tt.wantType {
+			case schema.MsgType_DEFINITION:
+				msg = new(schema.Definition)
+			case schema.MsgType_REFERENCES:
+				msg = new(schema.References)
+			default:
+				msg = new(schema.Hover)
+			}
+			if err := protodelim.UnmarshalFrom(in, msg); err != nil {
+				t.Fatal(err)
+			}
+			if empty := proto.Size(msg) == 0; empty != tt.wantEmpty {
+				t.Errorf("empty = %t, want %t: %v", empty, tt.wantEmpty, msg)
+			}
+			if in.Buffered() > 0 {
+				t.Errorf("runQuery() sent more than one message")
+			}
+		})
+	}
+}
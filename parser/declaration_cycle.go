@@ -2,81 +2,144 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
+type visitState int
+
+const (
+	unvisited visitState = iota
+	// on the stack of the current path
+	visiting
+	visited
+)
+
 type declCycleObj struct {
-	o        Object
-	hadError bool
+	o     Object
+	state visitState
 }
 
+// declarationCycleDetector finds types which contain themselves directly or through other types.
+// Such types would be infinitely large. References inside of list and map values are not followed,
+// because an empty list or map ends the recursion (e.g. `type node { children: list<node> }`).
 type declarationCycleDetector struct {
-	parser  *parser
-	objects map[string]*declCycleObj
+	parser *parser
+	// only types, because they are the only declarations which can contain other declarations
+	types   map[string]*declCycleObj
+	ordered []*declCycleObj
 
 	stack []*declCycleObj
+	// refs[i] is the property type in stack[i-1] referencing stack[i]
+	refs []Token
+
+	// every back edge closes a distinct cycle, which is reported once even if several properties use it
+	reported map[[2]*declCycleObj]bool
 }
 
 func (p *parser) detectDeclarationCycles() {
 	detector := &declarationCycleDetector{
 		parser: p,
+		types:  make(map[string]*declCycleObj),
 		stack:  make([]*declCycleObj, 0, 5),
+		refs:   make([]Token, 0, 5),
+
+		reported: make(map[[2]*declCycleObj]bool),
 	}
 
-	detector.objects = make(map[string]*declCycleObj, len(detector.parser.objects))
-	for _, o := range detector.parser.objects {
-		detector.objects[o.Name.Lexeme] = &declCycleObj{
+	for _, o := range p.objects {
+		if o.Type != TTType {
+			continue
+		}
+		obj := &declCycleObj{
 			o: o,
 		}
+		detector.types[o.Name.Lexeme] = obj
+		detector.ordered = append(detector.ordered, obj)
 	}
+	// inline types are appended before the type containing them
+	sort.SliceStable(detector.ordered, func(i, j int) bool {
+		a, b := detector.ordered[i].o.Name, detector.ordered[j].o.Name
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
 
 	detector.find()
 }
 
+// find visits the types in declaration order to report cycles deterministically.
 func (d *declarationCycleDetector) find() {
-	for _, o := range d.objects {
-		d.check(o)
+	for _, o := range d.ordered {
+		if o.state == unvisited {
+			d.check(o, Token{})
+		}
 	}
 }
 
-func (d *declarationCycleDetector) check(obj *declCycleObj) {
-	if obj.o.Type != TTType {
+// check walks all types contained in obj. ref is the property type which references obj.
+func (d *declarationCycleDetector) check(obj *declCycleObj, ref Token) {
+	if obj.state == visiting {
+		edge := [2]*declCycleObj{d.stack[len(d.stack)-1], obj}
+		if !d.reported[edge] {
+			d.reported[edge] = true
+			d.reportCycle(obj, ref)
+		}
 		return
 	}
-
-	if i := d.findInStack(obj); i != -1 {
-		if !obj.hadError {
-			obj.hadError = true
-			d.pushToStack(obj)
-			names := make([]string, len(d.stack)-i)
-			for j, o := range d.stack[i:len(d.stack)] {
-				names[j] = o.o.Name.Lexeme
-			}
-			d.parser.error(obj.o.Name, fmt.Sprintf("declaration cycle: %s", strings.Join(names, "->")), false)
-			d.popFromStack()
-		}
+	if obj.state == visited {
 		return
 	}
 
-	d.pushToStack(obj)
+	obj.state = visiting
+	d.pushToStack(obj, ref)
 
 	for _, p := range obj.o.Properties {
-		o, ok := d.objects[p.Type.Token.Lexeme]
-		if ok {
-			d.check(o)
+		token, ok := containedType(p.Type)
+		if !ok {
+			continue
+		}
+		if o, ok := d.types[token.Lexeme]; ok {
+			d.check(o, token)
 		}
 	}
 
 	d.popFromStack()
+	obj.state = visited
+}
+
+// containedType returns the type, whose values are directly contained in values of t.
+// Generic types like list and map do not contain their values directly.
+func containedType(t *PropertyType) (Token, bool) {
+	if t == nil || t.Generic != nil || t.Token.Type != TTIdentifier {
+		return Token{}, false
+	}
+	return t.Token, true
+}
+
+// reportCycle reports the cycle from obj (which is on the stack) to the top of the stack and back to obj.
+func (d *declarationCycleDetector) reportCycle(obj *declCycleObj, ref Token) {
+	i := d.findInStack(obj)
+	d.pushToStack(obj, ref)
+	names := make([]string, len(d.stack)-i)
+	for j, o := range d.stack[i:] {
+		names[j] = o.o.Name.Lexeme
+	}
+	related := make([]RelatedLocation, 0, len(d.stack)-i-1)
+	for j := i + 1; j < len(d.stack); j++ {
+		related = append(related, relatedToken(d.refs[j], fmt.Sprintf("'%s' uses '%s' here", d.stack[j-1].o.Name.Lexeme, d.stack[j].o.Name.Lexeme)))
+	}
+	d.parser.error(obj.o.Name, CodeDeclarationCycle, fmt.Sprintf("declaration cycle: %s", strings.Join(names, "->")), false, related...)
+	d.popFromStack()
 }
 
-func (d *declarationCycleDetector) pushToStack(obj *declCycleObj) {
+func (d *declarationCycleDetector) pushToStack(obj *declCycleObj, ref Token) {
 	d.stack = append(d.stack, obj)
+	d.refs = append(d.refs, ref)
 }
 
 func (d *declarationCycleDetector) popFromStack() *declCycleObj {
 	o := d.stack[len(d.stack)-1]
 	d.stack = d.stack[:len(d.stack)-1]
+	d.refs = d.refs[:len(d.refs)-1]
 	return o
 }
 
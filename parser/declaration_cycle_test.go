@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeclarationCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"self reference", "type a { x: a }", []string{"declaration cycle: a->a"}},
+		{"indirect", "type a { x: b }\ntype b { y: c }\ntype c { z: a }", []string{"declaration cycle: a->b->c->a"}},
+		{"through list", "type node { children: list<node> }", nil},
+		{"through map", "type a { x: b }\ntype b { y: map<a> }", nil},
+		{"nested generics", "type a { x: list<map<a>> }", nil},
+		{"inline type", "type a { x: type b { y: a } }", []string{"declaration cycle: a->b->a"}},
+		{"event with same name", "event a { x: a }\ntype a { y: int32 }", nil},
+		{"two cycles", "type a { x: a }\ntype b { y: c }\ntype c { z: b }", []string{"declaration cycle: a->a", "declaration cycle: b->c->b"}},
+		{"cycles sharing the start", "type a { x: b, y: c }\ntype b { z: a }\ntype c { w: a }", []string{"declaration cycle: a->b->a", "declaration cycle: a->c->a"}},
+		{"cycles sharing a type", "type a { x: b }\ntype b { y: a, z: c }\ntype c { w: b }", []string{"declaration cycle: a->b->a", "declaration cycle: b->c->b"}},
+		{"cycle through a reported cycle", "type a { x: b }\ntype b { y: a }\ntype c { z: a, w: c2 }\ntype c2 { v: c }", []string{"declaration cycle: a->b->a", "declaration cycle: c->c2->c"}},
+		// several references along the same edge form a single cycle
+		{"same edge twice", "type a { x: a, y: a }", []string{"declaration cycle: a->a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				rec := &recorder{}
+				err := Parse(strings.NewReader("cge 0.5\n"+tt.source), rec, Config{DisableWarnings: true})
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				got := make([]string, 0, len(rec.diagnostics))
+				for _, d := range rec.diagnostics {
+					got = append(got, d.Message)
+				}
+				if !equalStrings(got, tt.want) {
+					t.Fatalf("Parse() diagnostics = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
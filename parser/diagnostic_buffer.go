@@ -0,0 +1,51 @@
+package parser
+
+import "sort"
+
+// diagnosticBuffer holds back all diagnostics until the end of the file,
+// because suppression comments, sorting and the error limit depend on all diagnostics.
+type diagnosticBuffer struct {
+	Sender
+	pending []Diagnostic
+}
+
+func (b *diagnosticBuffer) SendDiagnostic(diagnostic Diagnostic) error {
+	b.pending = append(b.pending, diagnostic)
+	return nil
+}
+
+// flushDiagnostics sends all buffered diagnostics, which are not suppressed, ordered by position.
+func (p *parser) flushDiagnostics(buffer *diagnosticBuffer) {
+	diagnostics := finishDiagnostics(applySuppressions(p.suppressions, buffer.pending, p.config), p.config)
+	buffer.pending = nil
+	for _, d := range diagnostics {
+		p.sent(buffer.Sender.SendDiagnostic(d))
+		if p.sendErr != nil {
+			return
+		}
+	}
+}
+
+// finishDiagnostics sorts the diagnostics by position and drops all errors after the first MaxErrors.
+// Diagnostics at the same position keep their order.
+func finishDiagnostics(diagnostics []Diagnostic, config Config) []Diagnostic {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		return a.StartLine < b.StartLine || (a.StartLine == b.StartLine && a.StartColumn < b.StartColumn)
+	})
+	if config.MaxErrors <= 0 {
+		return diagnostics
+	}
+	result := diagnostics[:0]
+	errors := 0
+	for _, d := range diagnostics {
+		if d.Type == DiagnosticError {
+			if errors == config.MaxErrors {
+				continue
+			}
+			errors++
+		}
+		result = append(result, d)
+	}
+	return result
+}
@@ -0,0 +1,370 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiagnosticCode is the stable identifier of a kind of diagnostic. Codes are never reused or renumbered.
+type DiagnosticCode int
+
+const (
+	CodeUnknown DiagnosticCode = iota
+	CodeInvalidCharacter
+	CodeInvalidVersionNumber
+	CodeMissingVersion
+	CodeMalformedMetadata
+	CodeDuplicateMetadata
+	CodeIncompatibleVersion
+	CodeExpectedDeclaration
+	CodeMisplacedComment
+	CodeExpectedIdentifier
+	CodeExpectedBlock
+	CodeUnclosedBlock
+	CodeUndefinedType
+	CodeExpectedColon
+	CodeExpectedType
+	CodeMalformedGeneric
+	CodeDuplicateDeclaration
+	CodeDeclarationCycle
+	CodeDeprecatedGameComment
+	CodeDeprecatedNameField
+	CodeDeprecatedVersionField
+	CodeExpectedComma
+	CodeTypeAlias
+	CodeInvalidSuppression
+	CodeUnusedSuppression
+	CodeUnusedType
+	CodeEmptyEvent
+	CodeMissingDoc
+	CodeNamingConvention
+	CodeEnumMemberClash
+	CodeDeepNesting
+	CodeDuplicateProperty
+	CodePropertyNameClash
+	CodeLimitExceeded
+	CodeUnterminatedComment
+	CodeInvalidEncoding
+	CodeByteOrderMark
+	CodeInvalidIdentifier
+)
+
+type DiagnosticCategory int
+
+const (
+	CategorySyntax DiagnosticCategory = iota
+	CategorySemantic
+	CategoryLint
+	CategoryDeprecation
+)
+
+func (c DiagnosticCategory) String() string {
+	switch c {
+	case CategorySyntax:
+		return "syntax"
+	case CategorySemantic:
+		return "semantic"
+	case CategoryLint:
+		return "lint"
+	case CategoryDeprecation:
+		return "deprecation"
+	}
+	return "unknown"
+}
+
+type diagnosticInfo struct {
+	name        string
+	category    DiagnosticCategory
+	description string
+	example     string
+}
+
+var diagnosticInfos = map[DiagnosticCode]diagnosticInfo{
+	CodeInvalidCharacter: {
+		name:        "invalid-character",
+		category:    CategorySyntax,
+		description: "The file contains a character which is not part of the CGE syntax.\nComments start with '//' or '/*'.",
+		example:     "event player_joined {\n\tname: string,\n\tscore#: int\n}",
+	},
+	CodeInvalidVersionNumber: {
+		name:        "invalid-version-number",
+		category:    CategorySyntax,
+		description: "A number is not a valid version number. Version numbers consist of a major and a minor version separated by a dot.",
+		example:     "cge 0",
+	},
+	CodeMissingVersion: {
+		name:        "missing-version",
+		category:    CategorySyntax,
+		description: "Every CGE file must start with a 'cge' field specifying the version of the CGE language the file is written in.\nThe version of the parser is assumed for the rest of the file.",
+		example:     "event player_joined {\n\tname: string\n}",
+	},
+	CodeMalformedMetadata: {
+		name:        "malformed-metadata",
+		category:    CategorySyntax,
+		description: "A metadata field at the start of the file is missing its value.",
+		example:     "cge\n\nevent player_joined {\n\tname: string\n}",
+	},
+	CodeDuplicateMetadata: {
+		name:        "duplicate-metadata",
+		category:    CategorySyntax,
+		description: "The 'cge' metadata field may only be specified once.",
+		example:     "cge 0.5\ncge 0.4",
+	},
+	CodeIncompatibleVersion: {
+		name:        "incompatible-version",
+		category:    CategorySemantic,
+		description: "The file is written in a version of the CGE language, which is not supported by this parser.\nUse a parser matching the version of the file or update the 'cge' field.\nWith the --best-effort flag the file is parsed anyway and this diagnostic is reported as a warning.",
+		example:     "cge 9.0",
+	},
+	CodeExpectedDeclaration: {
+		name:        "expected-declaration",
+		category:    CategorySyntax,
+		description: "Only declarations ('config', 'command', 'event', 'type' and 'enum') are allowed at the top level of a file.",
+		example:     "cge 0.5\n\nplayer_joined {\n\tname: string\n}",
+	},
+	CodeMisplacedComment: {
+		name:        "misplaced-comment",
+		category:    CategorySyntax,
+		description: "Doc comments must be directly followed by the declaration or property they document.",
+	},
+	CodeExpectedIdentifier: {
+		name:        "expected-identifier",
+		category:    CategorySyntax,
+		description: "A name is missing or is not a valid identifier. Identifiers consist of lowercase letters, digits and underscores and must not start with a digit.",
+		example:     "cge 0.5\n\nevent {\n\tname: string\n}",
+	},
+	CodeExpectedBlock: {
+		name:        "expected-block",
+		category:    CategorySyntax,
+		description: "Every declaration needs a block enclosed in curly braces after its name, even if it is empty.",
+		example:     "cge 0.5\n\nevent player_left",
+	},
+	CodeUnclosedBlock: {
+		name:        "unclosed-block",
+		category:    CategorySyntax,
+		description: "A block is not closed with '}'.",
+		example:     "cge 0.5\n\nevent player_joined {\n\tname: string,\n\tscore: int",
+	},
+	CodeUndefinedType: {
+		name:        "undefined-type",
+		category:    CategorySemantic,
+		description: "A property uses a type, which is not declared anywhere in the file.\nDeclare it with 'type' or 'enum' or use one of the primitive types (string, bool, int, int32, int64, float, float32, float64, list, map).",
+		example:     "cge 0.5\n\nevent player_joined {\n\tplayer: player\n}",
+	},
+	CodeExpectedColon: {
+		name:        "expected-colon",
+		category:    CategorySyntax,
+		description: "The name of a property must be followed by a colon and its type.",
+		example:     "cge 0.5\n\nevent player_joined {\n\tname string\n}",
+	},
+	CodeExpectedType: {
+		name:        "expected-type",
+		category:    CategorySyntax,
+		description: "The type of a property is missing.",
+		example:     "cge 0.5\n\nevent player_joined {\n\tname:\n}",
+	},
+	CodeMalformedGeneric: {
+		name:        "malformed-generic",
+		category:    CategorySyntax,
+		description: "'list' and 'map' require the type of their values enclosed in angle brackets.",
+		example:     "cge 0.5\n\nevent scores {\n\tscores: map<int\n}",
+	},
+	CodeDuplicateDeclaration: {
+		name:        "duplicate-declaration",
+		category:    CategorySemantic,
+		description: "A name is declared more than once. Commands, events and types have separate namespaces, types and enums share one namespace.\nThere may only be a single config object.",
+		example:     "cge 0.5\n\ntype player {}\nenum player { red, blue }",
+	},
+	CodeDeclarationCycle: {
+		name:        "declaration-cycle",
+		category:    CategorySemantic,
+		description: "A type contains itself directly or through other types, which would make every value of the type infinitely large.\nRecursion through lists and maps is allowed, because they can be empty (e.g. 'children: list<node>').",
+		example:     "cge 0.5\n\ntype a {\n\tb: b\n}\n\ntype b {\n\ta: a\n}",
+	},
+	CodeDeprecatedGameComment: {
+		name:        "deprecated-game-comment",
+		category:    CategoryDeprecation,
+		description: "Comments before the metadata fields used to describe the game. They are ignored.",
+		example:     "// My game.\ncge 0.5",
+	},
+	CodeDeprecatedNameField: {
+		name:        "deprecated-name-field",
+		category:    CategoryDeprecation,
+		description: "The 'name' metadata field is no longer used and can be removed.",
+		example:     "name my_game\ncge 0.5",
+	},
+	CodeDeprecatedVersionField: {
+		name:        "deprecated-version-field",
+		category:    CategoryDeprecation,
+		description: "The 'version' metadata field was renamed to 'cge'.",
+		example:     "version 0.5",
+	},
+	CodeExpectedComma: {
+		name:        "expected-comma",
+		category:    CategorySyntax,
+		description: "Properties and enum values inside of a block must be separated by commas.",
+		example:     "cge 0.5\n\nenum color {\n\tred\n\tblue\n}",
+	},
+	CodeTypeAlias: {
+		name:        "type-alias",
+		category:    CategoryLint,
+		description: "'int' and 'float' are aliases of 'int32' and 'float64'. Use the canonical names to make the size of the number explicit.",
+		example:     "cge 0.5\n\nevent scored {\n\tpoints: int\n}",
+	},
+	CodeInvalidSuppression: {
+		name:        "invalid-suppression",
+		category:    CategoryLint,
+		description: "A suppression comment does not list any diagnostic codes or contains an unknown code.\nCodes can be given as 'CGE0021' or by name like 'type-alias'.",
+		example:     "cge 0.5\n\n// cge:ignore CGE9999\nevent scored {\n\tpoints: int\n}",
+	},
+	CodeUnusedSuppression: {
+		name:        "unused-suppression",
+		category:    CategoryLint,
+		description: "A suppression comment does not suppress any diagnostic. 'cge:ignore' applies to the line of the comment and the following line, 'cge:ignore-file' to the whole file.\nErrors cannot be suppressed.",
+		example:     "cge 0.5\n\n// cge:ignore type-alias\nevent scored {\n\tpoints: int32\n}",
+	},
+	CodeUnusedType: {
+		name:        "unused-type",
+		category:    CategoryLint,
+		description: "A type or enum is not used by any other declaration.",
+		example:     "cge 0.5\n\ntype position {\n\tx: float64,\n\ty: float64\n}",
+	},
+	CodeEmptyEvent: {
+		name:        "empty-event",
+		category:    CategoryLint,
+		description: "An event does not have any properties. Disabled by default.",
+		example:     "cge 0.5\n\nevent game_started {}",
+	},
+	CodeMissingDoc: {
+		name:        "missing-doc",
+		category:    CategoryLint,
+		description: "A declaration does not have a doc comment. Disabled by default.",
+		example:     "cge 0.5\n\nevent game_started {}",
+	},
+	CodeNamingConvention: {
+		name:        "naming-convention",
+		category:    CategoryLint,
+		description: "Names should be snake_case: words separated by single underscores without leading or trailing underscores.",
+		example:     "cge 0.5\n\nevent player__joined {\n\t_name: string\n}",
+	},
+	CodeEnumMemberClash: {
+		name:        "enum-member-type-clash",
+		category:    CategoryLint,
+		description: "An enum member has the same name as a type or enum, which causes name clashes in some target languages.",
+		example:     "cge 0.5\n\nenum shape { circle, square }\n\ntype circle {\n\tradius: float64\n}",
+	},
+	CodeDeepNesting: {
+		name:        "deep-nesting",
+		category:    CategoryLint,
+		description: "A property type nests generics and inline declarations deeper than the configured maximum (3 by default).",
+		example:     "cge 0.5\n\nevent board {\n\tcells: list<list<list<map<int32>>>>\n}",
+	},
+	CodeDuplicateProperty: {
+		name:        "duplicate-property",
+		category:    CategorySemantic,
+		description: "A property or enum member is defined more than once in the same block.",
+		example:     "cge 0.5\n\nevent hit {\n\tdamage: int32,\n\tdamage: float64\n}",
+	},
+	CodePropertyNameClash: {
+		name:        "property-name-clash",
+		category:    CategorySemantic,
+		description: "Two properties or enum members of the same block have the same name when converted to PascalCase (ignoring case and underscores),\nwhich breaks the generated code in many target languages.",
+		example:     "cge 0.5\n\nenum color {\n\tdark_red,\n\tdarkred\n}",
+	},
+	CodeLimitExceeded: {
+		name:        "limit-exceeded",
+		category:    CategorySyntax,
+		description: "The input exceeds one of the configured resource limits (size, tokens, declarations, nesting depth or identifier length).\nParsing stops when the size, token or declaration limit is reached.",
+		example:     "cge 0.5\n\nevent board {\n\tcells: list<list<list<list<list<list<string>>>>>>\n}",
+	},
+	CodeUnterminatedComment: {
+		name:        "unterminated-comment",
+		category:    CategorySyntax,
+		description: "A block comment is not closed with '*/' before the end of the file.",
+		example:     "cge 0.5\n\n/* Sent when a player joins.\nevent player_joined {\n\tname: string\n}",
+	},
+	CodeInvalidEncoding: {
+		name:        "invalid-encoding",
+		category:    CategorySyntax,
+		description: "The file contains bytes which are not valid UTF-8. CGE files must be encoded in UTF-8.",
+	},
+	CodeByteOrderMark: {
+		name:        "byte-order-mark",
+		category:    CategorySyntax,
+		description: "The file starts with a UTF-8 byte order mark. CGE files must be encoded in UTF-8 without a byte order mark.",
+	},
+	CodeInvalidIdentifier: {
+		name:        "invalid-identifier",
+		category:    CategorySyntax,
+		description: "An identifier contains uppercase letters. Identifiers may only contain lowercase letters, digits and underscores.",
+		example:     "cge 0.5\n\nevent PlayerJoined {\n\tplayerId: string\n}",
+	},
+}
+
+// String returns the code in the format 'CGE0012'.
+func (c DiagnosticCode) String() string {
+	return fmt.Sprintf("CGE%04d", int(c))
+}
+
+// Name returns a short human-readable name like 'undefined-type'.
+func (c DiagnosticCode) Name() string {
+	if info, ok := diagnosticInfos[c]; ok {
+		return info.name
+	}
+	return "unknown"
+}
+
+func (c DiagnosticCode) Category() DiagnosticCategory {
+	return diagnosticInfos[c].category
+}
+
+// Explain returns a long description of the diagnostic including an example.
+func (c DiagnosticCode) Explain() string {
+	info, ok := diagnosticInfos[c]
+	if !ok {
+		return ""
+	}
+	text := fmt.Sprintf("%s %s (%s)\n\n%s\n", c, info.name, info.category, info.description)
+	if info.example != "" {
+		text += "\nExample:\n\n"
+		for _, line := range strings.Split(info.example, "\n") {
+			if line != "" {
+				line = "\t" + line
+			}
+			text += line + "\n"
+		}
+	}
+	return text
+}
+
+// ParseDiagnosticCode accepts codes like 'CGE0012' (case insensitive, leading zeros optional) and names like 'undefined-type'.
+func ParseDiagnosticCode(code string) (DiagnosticCode, bool) {
+	upper := strings.ToUpper(code)
+	if strings.HasPrefix(upper, "CGE") {
+		n, err := strconv.Atoi(upper[3:])
+		if err == nil {
+			_, ok := diagnosticInfos[DiagnosticCode(n)]
+			return DiagnosticCode(n), ok
+		}
+	}
+	for c, info := range diagnosticInfos {
+		if info.name == code {
+			return c, true
+		}
+	}
+	return CodeUnknown, false
+}
+
+// DiagnosticCodes returns all known diagnostic codes in ascending order.
+func DiagnosticCodes() []DiagnosticCode {
+	codes := make([]DiagnosticCode, 0, len(diagnosticInfos))
+	for c := range diagnosticInfos {
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+	return codes
+}
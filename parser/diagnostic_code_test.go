@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticCodes(t *testing.T) {
+	// codes are part of the public interface and must never be renumbered or renamed
+	want := []struct {
+		code DiagnosticCode
+		str  string
+		name string
+	}{
+		{CodeInvalidCharacter, "CGE0001", "invalid-character"},
+		{CodeInvalidVersionNumber, "CGE0002", "invalid-version-number"},
+		{CodeMissingVersion, "CGE0003", "missing-version"},
+		{CodeMalformedMetadata, "CGE0004", "malformed-metadata"},
+		{CodeDuplicateMetadata, "CGE0005", "duplicate-metadata"},
+		{CodeIncompatibleVersion, "CGE0006", "incompatible-version"},
+		{CodeExpectedDeclaration, "CGE0007", "expected-declaration"},
+		{CodeMisplacedComment, "CGE0008", "misplaced-comment"},
+		{CodeExpectedIdentifier, "CGE0009", "expected-identifier"},
+		{CodeExpectedBlock, "CGE0010", "expected-block"},
+		{CodeUnclosedBlock, "CGE0011", "unclosed-block"},
+		{CodeUndefinedType, "CGE0012", "undefined-type"},
+		{CodeExpectedColon, "CGE0013", "expected-colon"},
+		{CodeExpectedType, "CGE0014", "expected-type"},
+		{CodeMalformedGeneric, "CGE0015", "malformed-generic"},
+		{CodeDuplicateDeclaration, "CGE0016", "duplicate-declaration"},
+		{CodeDeclarationCycle, "CGE0017", "declaration-cycle"},
+		{CodeDeprecatedGameComment, "CGE0018", "deprecated-game-comment"},
+		{CodeDeprecatedNameField, "CGE0019", "deprecated-name-field"},
+		{CodeDeprecatedVersionField, "CGE0020", "deprecated-version-field"},
+		{CodeExpectedComma, "CGE0021", "expected-comma"},
+		{CodeTypeAlias, "CGE0022", "type-alias"},
+		{CodeInvalidSuppression, "CGE0023", "invalid-suppression"},
+		{CodeUnusedSuppression, "CGE0024", "unused-suppression"},
+		{CodeUnusedType, "CGE0025", "unused-type"},
+		{CodeEmptyEvent, "CGE0026", "empty-event"},
+		{CodeMissingDoc, "CGE0027", "missing-doc"},
+		{CodeNamingConvention, "CGE0028", "naming-convention"},
+		{CodeEnumMemberClash, "CGE0029", "enum-member-type-clash"},
+		{CodeDeepNesting, "CGE0030", "deep-nesting"},
+		{CodeDuplicateProperty, "CGE0031", "duplicate-property"},
+		{CodePropertyNameClash, "CGE0032", "property-name-clash"},
+		{CodeLimitExceeded, "CGE0033", "limit-exceeded"},
+		{CodeUnterminatedComment, "CGE0034", "unterminated-comment"},
+		{CodeInvalidEncoding, "CGE0035", "invalid-encoding"},
+		{CodeByteOrderMark, "CGE0036", "byte-order-mark"},
+		{CodeInvalidIdentifier, "CGE0037", "invalid-identifier"},
+	}
+
+	codes := DiagnosticCodes()
+	if len(codes) != len(want) {
+		t.Fatalf("DiagnosticCodes() returned %d codes, want %d", len(codes), len(want))
+	}
+	names := make(map[string]DiagnosticCode)
+	for i, c := range codes {
+		w := want[i]
+		if c != w.code || c.String() != w.str || c.Name() != w.name {
+			t.Errorf("DiagnosticCodes()[%d] = %s %s, want %s %s", i, c, c.Name(), w.str, w.name)
+		}
+		if other, ok := names[c.Name()]; ok {
+			t.Errorf("%s and %s have the same name %q", other, c, c.Name())
+		}
+		names[c.Name()] = c
+
+		if got, ok := ParseDiagnosticCode(c.String()); !ok || got != c {
+			t.Errorf("ParseDiagnosticCode(%q) = %s, %t", c.String(), got, ok)
+		}
+		if got, ok := ParseDiagnosticCode(c.Name()); !ok || got != c {
+			t.Errorf("ParseDiagnosticCode(%q) = %s, %t", c.Name(), got, ok)
+		}
+		if diagnosticInfos[c].description == "" || !strings.HasPrefix(c.Explain(), c.String()+" "+c.Name()) {
+			t.Errorf("%s has no explanation: %q", c, c.Explain())
+		}
+	}
+
+	if _, ok := ParseDiagnosticCode(CodeUnknown.String()); ok {
+		t.Errorf("ParseDiagnosticCode(%q) succeeded for CodeUnknown", CodeUnknown.String())
+	}
+}
+
+// TestEmittedDiagnosticCodes checks that all diagnostics reported for a variety of inputs use a registered code.
+func TestEmittedDiagnosticCodes(t *testing.T) {
+	sources := append([]string{
+		"cge 0.5\nevent e {}\ntype T_x { a: int }\nenum c { t }\ntype t {}\nevent f { x: list<list<list<list<int32>>>> }\n",
+		"cge 0.5\n// cge:ignore\nevent a { b: string } // cge:ignore unused-type\n",
+		"cge 0.5\ncommand a {}\ncommand a {}\nconfig {}\nconfig {}\n",
+	}, fuzzSeeds...)
+	files, err := filepath.Glob("../conformance/corpus/*.cge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("conformance corpus not found")
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, string(data))
+	}
+
+	allRules := make(map[DiagnosticCode]LintSeverity)
+	for code := range defaultLintSeverities {
+		allRules[code] = LintInfo
+	}
+	configs := []Config{
+		{},
+		{ErrorTolerant: true, Lint: LintConfig{Severities: allRules, MaxNestingDepth: 1}},
+		{Limits: Limits{MaxTokens: 8, MaxIdentifierLength: 3}},
+	}
+
+	registered := make(map[DiagnosticCode]bool)
+	for _, c := range DiagnosticCodes() {
+		registered[c] = true
+	}
+	emitted := make(map[DiagnosticCode]bool)
+	for _, source := range sources {
+		for _, config := range configs {
+			rec := &recorder{}
+			if err := Parse(strings.NewReader(source), rec, config); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			for _, d := range rec.diagnostics {
+				emitted[d.Code] = true
+				if !registered[d.Code] {
+					t.Errorf("diagnostic %q uses unregistered code %s", d.Message, d.Code)
+				}
+			}
+		}
+	}
+	if len(emitted) < len(registered)/2 {
+		t.Errorf("only %d of %d codes were emitted", len(emitted), len(registered))
+	}
+}
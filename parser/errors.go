@@ -0,0 +1,61 @@
+package parser
+
+import "fmt"
+
+// ReadError is returned if the input could not be read.
+type ReadError struct {
+	Err error
+}
+
+func (e *ReadError) Error() string {
+	return fmt.Sprintf("failed to read input data: %s", e.Err)
+}
+
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// SendError is returned if the Sender failed. Parsing stops after the first failure.
+type SendError struct {
+	Err error
+}
+
+func (e *SendError) Error() string {
+	return fmt.Sprintf("failed to send output: %s", e.Err)
+}
+
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
+
+// CancelError is returned if the context was canceled or its deadline exceeded before parsing finished.
+// Err is the error of the context.
+type CancelError struct {
+	Err error
+}
+
+func (e *CancelError) Error() string {
+	return fmt.Sprintf("parsing canceled: %s", e.Err)
+}
+
+func (e *CancelError) Unwrap() error {
+	return e.Err
+}
+
+// sent records the first error returned by the Sender.
+func (p *parser) sent(err error) {
+	if err != nil && p.sendErr == nil {
+		p.sendErr = err
+	}
+}
+
+// failure returns the reason, why parsing has to stop, or nil.
+func (p *parser) failure() error {
+	if p.scanner != nil && p.scanner.err != nil {
+		return &ReadError{Err: p.scanner.err}
+	}
+	if p.sendErr != nil {
+		return &SendError{Err: p.sendErr}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type failingReader struct {
+	data string
+	err  error
+}
+
+func (r *failingReader) Read(b []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(b, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+type failingSender struct {
+	recorder
+	err error
+}
+
+func (s *failingSender) SendObject(object Object) error {
+	return s.err
+}
+
+func TestParseErrors(t *testing.T) {
+	errRead := errors.New("connection reset")
+	rec := &recorder{}
+	err := Parse(&failingReader{data: "cge 0.5\nevent a {", err: errRead}, rec, Config{})
+	var readErr *ReadError
+	if !errors.As(err, &readErr) || !errors.Is(err, errRead) {
+		t.Errorf("Parse() with failing reader error = %v, want *ReadError", err)
+	}
+	if len(rec.diagnostics) > 0 {
+		t.Errorf("Parse() with failing reader reported %v", diagnosticSummary(rec.diagnostics))
+	}
+
+	errSend := errors.New("broken pipe")
+	err = Parse(strings.NewReader("cge 0.5\nevent a {}\n"), &failingSender{err: errSend}, Config{})
+	var sendErr *SendError
+	if !errors.As(err, &sendErr) || !errors.Is(err, errSend) {
+		t.Errorf("Parse() with failing sender error = %v, want *SendError", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ParseContext(ctx, strings.NewReader("cge 0.5\nevent a {}\n"), &recorder{}, Config{})
+	var cancelErr *CancelError
+	if !errors.As(err, &cancelErr) || !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseContext() with canceled context error = %v, want *CancelError", err)
+	}
+
+	err = Format(&failingReader{data: "cge 0.5\n", err: errRead}, io.Discard, "\t")
+	if !errors.As(err, &readErr) {
+		t.Errorf("Format() with failing reader error = %v, want *ReadError", err)
+	}
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+)
+
+// maxFixIterations limits the number of times FixSource reparses the source.
+const maxFixIterations = 32
+
+// FixSource applies all safe fixes to source and returns the result with the number of applied fixes.
+// Fixes overlapping other fixes are applied in a later pass. The result does not contain any safe fixes,
+// so fixing it again is a no-op.
+func FixSource(source string) (string, int, error) {
+	tree, err := parseTree(source, Config{
+		BestEffort: true,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	applied := 0
+	for i := 0; i < maxFixIterations; i++ {
+		edits, count := safeEdits(tree.Diagnostics())
+		if count == 0 {
+			return tree.Source(), applied, nil
+		}
+		// apply from back to front, so that the positions of the remaining edits stay valid
+		for j := len(edits) - 1; j >= 0; j-- {
+			tree, _, err = tree.Edit(edits[j])
+			if err != nil {
+				return "", 0, fmt.Errorf("failed to apply fix: %w", err)
+			}
+		}
+		applied += count
+	}
+	return "", 0, fmt.Errorf("safe fixes did not converge after %d passes", maxFixIterations)
+}
+
+// safeEdits returns the sorted edits of all safe fixes, which do not overlap with each other, and the number of these fixes.
+func safeEdits(diagnostics []Diagnostic) ([]Edit, int) {
+	edits := make([]Edit, 0)
+	count := 0
+	for _, d := range diagnostics {
+		fix, ok := firstSafeFix(d)
+		if !ok || overlapsAny(fix.Edits, edits) {
+			continue
+		}
+		edits = append(edits, fix.Edits...)
+		count++
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		return pos{edits[i].StartLine, edits[i].StartColumn}.before(pos{edits[j].StartLine, edits[j].StartColumn})
+	})
+	return edits, count
+}
+
+func firstSafeFix(diagnostic Diagnostic) (Fix, bool) {
+	for _, f := range diagnostic.Fixes {
+		if f.Safe {
+			return f, true
+		}
+	}
+	return Fix{}, false
+}
+
+func overlapsAny(edits, others []Edit) bool {
+	for _, e := range edits {
+		for _, other := range others {
+			if editsOverlap(e, other) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// editsOverlap also reports edits starting at the same position, because their order would be ambiguous.
+func editsOverlap(a, b Edit) bool {
+	aStart, aEnd := pos{a.StartLine, a.StartColumn}, pos{a.EndLine, a.EndColumn}
+	bStart, bEnd := pos{b.StartLine, b.StartColumn}, pos{b.EndLine, b.EndColumn}
+	return aStart == bStart || (aStart.before(bEnd) && bStart.before(aEnd))
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFixSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"deprecated header", "// My game.\nname my_game\nversion 0.5\n\nevent a {}\n", "cge 0.5\n\nevent a {}\n"},
+		{"type aliases", "cge 0.5\nevent a { x: int, y: map<float> }\n", "cge 0.5\nevent a { x: int32, y: map<float64> }\n"},
+		{"missing separators", "cge 0.5\nenum a { x y z }\ntype b {\n\tx int\n\t// doc\n\ty: string\n}\n", "cge 0.5\nenum a { x, y, z }\ntype b {\n\tx: int32,\n\t// doc\n\ty: string\n}\n"},
+		{"unsafe fixes", "cge 0.5\nevent a { x: list<string }\n", "cge 0.5\nevent a { x: list<string }\n"},
+		{"no fixes", "cge 0.5\n\n// A.\ntype a {\n\tx: int32,\n}\n", "cge 0.5\n\n// A.\ntype a {\n\tx: int32,\n}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := FixSource(tt.source)
+			if err != nil {
+				t.Fatalf("FixSource() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FixSource() = %q, want %q", got, tt.want)
+			}
+
+			again, count, err := FixSource(got)
+			if err != nil {
+				t.Fatalf("second FixSource() error = %v", err)
+			}
+			if count != 0 || again != got {
+				t.Errorf("second FixSource() applied %d fixes: %q", count, again)
+			}
+		})
+	}
+}
+
+func fixSummary(f Fix) string {
+	summary := fmt.Sprintf("%q safe=%t", f.Title, f.Safe)
+	for _, e := range f.Edits {
+		summary += fmt.Sprintf(" %d:%d-%d:%d %q", e.StartLine, e.StartColumn, e.EndLine, e.EndColumn, e.Text)
+	}
+	return summary
+}
+
+func TestFixes(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		code   DiagnosticCode
+		want   string
+	}{
+		{"deprecated game comment", "// My game.\nname my_game\ncge 0.5\nevent a {}\n", CodeDeprecatedGameComment, `"Remove game comment" safe=true 0:0-1:0 ""`},
+		{"deprecated name field", "name my_game\ncge 0.5\nevent a {}\n", CodeDeprecatedNameField, `"Remove 'name' field" safe=true 0:0-1:0 ""`},
+		{"deprecated version field", "version 0.5\nevent a {}\n", CodeDeprecatedVersionField, `"Replace 'version' with 'cge'" safe=true 0:0-0:7 "cge"`},
+		{"undefined type", "cge 0.5\ntype player {}\nevent a { x: playr, y: list<playr> }\n", CodeUndefinedType, `"Replace with 'player'" safe=false 2:13-2:18 "player" 2:28-2:33 "player"`},
+		{"misspelled keyword", "cge 0.5\nevnt a {}\n", CodeExpectedDeclaration, `"Replace with 'event'" safe=false 1:0-1:4 "event"`},
+		{"unclosed block", "cge 0.5\nevent a { x: int32\n", CodeUnclosedBlock, `"Insert '}'" safe=false 1:18-1:18 "}"`},
+		{"missing colon", "cge 0.5\nevent a { x int32 }\n", CodeExpectedColon, `"Insert ':'" safe=true 1:11-1:11 ":"`},
+		{"type alias", "cge 0.5\nevent a { x: int }\n", CodeTypeAlias, `"Replace with 'int32'" safe=true 1:13-1:16 "int32"`},
+		{"unclosed generic", "cge 0.5\nevent a { x: list<int32 }\n", CodeMalformedGeneric, `"Insert '>'" safe=false 1:23-1:23 ">"`},
+		{"missing comma", "cge 0.5\nevent a { x: int32 y: int32 }\n", CodeExpectedComma, `"Insert ','" safe=true 1:18-1:18 ","`},
+		{"naming convention", "cge 0.5\nevent a { x__y: int32 }\n", CodeNamingConvention, `"Rename to 'x_y'" safe=false 1:10-1:14 "x_y"`},
+		{"byte order mark", "\uFEFFcge 0.5\n", CodeByteOrderMark, `"Remove byte order mark" safe=false 0:0-0:1 ""`},
+		{"invalid identifier", "cge 0.5\nevent PlayerJoined {}\n", CodeInvalidIdentifier, `"Rename to 'player_joined'" safe=false 1:6-1:18 "player_joined"`},
+		{"unterminated comment", "cge 0.5\n/* abc", CodeUnterminatedComment, `"Insert '*/'" safe=false 1:6-1:6 "*/"`},
+		{"unused suppression", "cge 0.5\n// cge:ignore type-alias\nevent a { x: int32 }\n", CodeUnusedSuppression, `"Remove suppression comment" safe=false 1:0-1:24 ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			if err := Parse(strings.NewReader(tt.source), rec, Config{}); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var fix *Fix
+			for _, d := range rec.diagnostics {
+				if d.Code == tt.code && len(d.Fixes) > 0 {
+					fix = &d.Fixes[0]
+					break
+				}
+			}
+			if fix == nil {
+				t.Fatalf("no %s diagnostic with a fix in %v", tt.code, diagnosticSummary(rec.diagnostics))
+			}
+			if got := fixSummary(*fix); got != tt.want {
+				t.Errorf("fix = %s, want %s", got, tt.want)
+			}
+
+			fixed := tt.source
+			for i := len(fix.Edits) - 1; i >= 0; i-- {
+				fixed = applyEdit(t, fixed, fix.Edits[i])
+			}
+			rec = &recorder{}
+			if err := Parse(strings.NewReader(fixed), rec, Config{}); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			for _, d := range rec.diagnostics {
+				if d.Code == tt.code {
+					t.Errorf("%s is still reported after applying the fix: %q", tt.code, fixed)
+				}
+			}
+		})
+	}
+}
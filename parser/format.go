@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format reads a CGE file from input and writes it to output in canonical form.
+// Only whitespace is changed. Comments and the token sequence are preserved.
+func Format(input io.Reader, output io.Writer, indent string) error {
+	s := newScanner(input, Limits{})
+	tokens := make([]Token, 0, 64)
+	for {
+		token := s.nextToken()
+		if token.Type == TTError {
+			return fmt.Errorf("[%d:%d] %s", token.Line, token.Column, token.Lexeme)
+		}
+		if token.Type == TTEOF {
+			if s.err != nil {
+				return &ReadError{Err: s.err}
+			}
+			break
+		}
+		tokens = append(tokens, token)
+	}
+
+	_, err := io.WriteString(output, formatTokens(tokens, indent))
+	return err
+}
+
+func formatTokens(tokens []Token, indent string) string {
+	var b strings.Builder
+
+	depth := 0
+	for i, token := range tokens {
+		if token.Type == TTCloseCurly && depth > 0 {
+			depth--
+		}
+
+		if i > 0 {
+			prev := tokens[i-1]
+			switch {
+			case needsNewline(tokens, i, depth):
+				b.WriteByte('\n')
+				if token.Line-prev.EndLine > 1 && prev.Type != TTOpenCurly && token.Type != TTCloseCurly {
+					b.WriteByte('\n')
+				}
+				b.WriteString(strings.Repeat(indent, depth))
+			case needsSpace(prev, token):
+				b.WriteByte(' ')
+			}
+		}
+
+		b.WriteString(token.Lexeme)
+
+		if token.Type == TTOpenCurly {
+			depth++
+		}
+	}
+
+	if len(tokens) > 0 {
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func needsNewline(tokens []Token, i, depth int) bool {
+	prev := tokens[i-1]
+	token := tokens[i]
+
+	if token.Type == TTComment || prev.Type == TTComment {
+		if prev.Type == TTComment && strings.HasPrefix(prev.Lexeme, "//") {
+			return true
+		}
+		if token.Line > prev.EndLine {
+			return true
+		}
+		return false
+	}
+
+	switch {
+	case prev.Type == TTOpenCurly:
+		return token.Type != TTCloseCurly
+	case token.Type == TTCloseCurly:
+		return true
+	case prev.Type == TTComma:
+		return depth > 0
+	case prev.Type == TTCloseCurly:
+		return depth == 0
+	case prev.Type == TTVersionNumber:
+		return true
+	case prev.Type == TTIdentifier && i > 1 && tokens[i-2].Type == TTGameName:
+		return true
+	}
+	return false
+}
+
+func needsSpace(prev, token Token) bool {
+	switch token.Type {
+	case TTColon, TTComma, TTLess, TTGreater:
+		return false
+	case TTCloseCurly:
+		return prev.Type != TTOpenCurly
+	}
+	return prev.Type != TTLess
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var fuzzSeeds = []string{
+	"",
+	"cge 0.5\n",
+	incrementalSource,
+	"cge 0.5\nevent a { x: list<map<type b { y: enum c { d } }>> }\n",
+	"name game\nversion 0.4\n// comment\nconfig { x: int }\n",
+	"cge 0.5\n/* unterminated\nevent a {}",
+	"\uFEFFcge 0.5\nevent PlayerJoined { playerId: string / }\n",
+	"cge 0.5\n// cge:ignore CGE0022\nevent a { x: int, }\r\n",
+	"cge 1x\nevent a { x: \xff }\n",
+	"/",
+}
+
+func FuzzScanner(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, source string) {
+		s := newStringScanner(source, Limits{})
+		// every token except EOF and recoverable errors consumes at least one rune
+		maxTokens := 2*utf8.RuneCountInString(source) + 2
+		for i := 0; ; i++ {
+			if i > maxTokens {
+				t.Fatalf("scanner does not reach EOF")
+			}
+			token := s.nextToken()
+			if (pos{token.EndLine, token.EndColumn}).before(pos{token.Line, token.Column}) {
+				t.Fatalf("token %q ends at %d:%d before its start %d:%d", token.Lexeme, token.EndLine, token.EndColumn, token.Line, token.Column)
+			}
+			if token.Type == TTEOF {
+				break
+			}
+		}
+	})
+}
+
+// fuzzSender records the output of Parse.
+type fuzzSender struct {
+	recorder
+	semanticTokens []SemanticToken
+	objects        []Object
+}
+
+func (s *fuzzSender) SendSemanticToken(token SemanticToken) error {
+	s.semanticTokens = append(s.semanticTokens, token)
+	return nil
+}
+
+func (s *fuzzSender) SendObject(object Object) error {
+	s.objects = append(s.objects, object)
+	return nil
+}
+
+// FuzzParse checks that no input and configuration makes Parse panic or fail.
+func FuzzParse(f *testing.F) {
+	for i, seed := range fuzzSeeds {
+		f.Add(seed, uint16(i*37))
+	}
+	f.Fuzz(func(t *testing.T, source string, flags uint16) {
+		config := Config{
+			IncludeComments:    flags&1 != 0,
+			SendTokens:         flags&2 != 0,
+			SendSemanticTokens: flags&4 != 0,
+			ErrorTolerant:      flags&8 != 0,
+			BestEffort:         flags&16 != 0,
+			OnlyMetadata:       flags&32 != 0,
+			OneBased:           flags&64 != 0,
+			PositionEncoding:   PositionEncoding(flags >> 7 % 3),
+			MaxErrors:          int(flags >> 9 % 4),
+		}
+		if flags&(1<<11) != 0 {
+			config.Limits = Limits{MaxBytes: 64, MaxTokens: 32, MaxNestingDepth: 2, MaxDeclarations: 3, MaxIdentifierLength: 8}
+		}
+		if flags&(1<<12) != 0 {
+			for _, code := range DiagnosticCodes() {
+				config.Lint.Set(code.Name(), "warning")
+			}
+		}
+
+		out := &fuzzSender{}
+		if err := Parse(strings.NewReader(source), out, config); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		for _, d := range out.diagnostics {
+			if d.StartOffset < 0 || d.StartOffset > d.EndOffset || d.EndOffset > len(source) {
+				t.Fatalf("diagnostic %q has invalid offsets %d-%d", d.Message, d.StartOffset, d.EndOffset)
+			}
+		}
+		for _, token := range out.tokens {
+			if token.Offset < 0 || token.Offset > len(source) {
+				t.Fatalf("token %q has invalid offset %d", token.Lexeme, token.Offset)
+			}
+		}
+
+		tree, err := ParseTree(strings.NewReader(source), config)
+		if err != nil {
+			t.Fatalf("ParseTree() error = %v", err)
+		}
+		tree.Diagnostics()
+		tree.SemanticTokens()
+		Format(strings.NewReader(source), &strings.Builder{}, "\t")
+	})
+}
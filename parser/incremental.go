@@ -0,0 +1,667 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Edit replaces the text between the start and the end position (exclusive) with Text.
+// Positions use the same line and column numbering as tokens (see Config.PositionEncoding and Config.OneBased).
+type Edit struct {
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+	// byte offsets (only set in the output of Parse, ignored by Tree.Edit)
+	StartOffset int
+	EndOffset   int
+	Text        string
+}
+
+// Update describes the region of a tree which was reparsed by Tree.Edit.
+type Update struct {
+	// inclusive
+	StartLine   int
+	StartColumn int
+	// exclusive
+	EndLine   int
+	EndColumn int
+
+	// Objects declared in the reparsed region.
+	Objects []Object
+	// Diagnostics of the reparsed region followed by the diagnostics of all checks spanning multiple declarations.
+	// Suppression comments are not applied.
+	Diagnostics []Diagnostic
+	// Number of declarations, whose parse result was reused.
+	Reused int
+}
+
+// Tree is the parse result of a complete CGE file, which can be updated incrementally with Edit.
+// OnlyMetadata, SendTokens and NoObjects of the config are ignored.
+// All positions in the output and in edits use the configured position encoding.
+type Tree struct {
+	config     Config
+	source     string
+	lineStarts []int
+
+	cgeVersion string
+	gameName   Token
+	header     segment
+	// false if parsing stopped after the metadata
+	body     bool
+	segments []segment
+
+	objects          []Object
+	checkDiagnostics []Diagnostic
+}
+
+// segment is the result of parsing a single top-level declaration including its error recovery.
+type segment struct {
+	start                   pos
+	tokens                  []Token
+	diagnostics             []Diagnostic
+	objects                 []Object
+	accessedTypeIdentifiers []Token
+}
+
+// firstTokenEnd returns the end of the first token of the segment.
+func (s segment) firstTokenEnd() pos {
+	if len(s.tokens) == 0 {
+		return s.start
+	}
+	return pos{s.tokens[0].EndLine, s.tokens[0].EndColumn}
+}
+
+type pos struct {
+	line   int
+	column int
+}
+
+func (p pos) before(other pos) bool {
+	return p.line < other.line || (p.line == other.line && p.column < other.column)
+}
+
+func ParseTree(input io.Reader, config Config) (*Tree, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, &ReadError{Err: err}
+	}
+	return parseTree(string(data), config)
+}
+
+func parseTree(source string, config Config) (*Tree, error) {
+	t := &Tree{
+		config:     config,
+		source:     source,
+		lineStarts: lineStarts(source),
+	}
+
+	p, rec := t.newParser(0, pos{})
+	err := t.parseHeader(p, rec)
+	if err != nil || !t.body {
+		return t, err
+	}
+
+	t.segments, _ = t.parseSegments(p, rec, nil)
+	t.check()
+	return t, nil
+}
+
+// parseHeader parses the metadata and sets body if the body should be parsed.
+func (t *Tree) parseHeader(p *parser, rec *recorder) error {
+	err := p.metadata()
+	t.cgeVersion = rec.cgeVersion
+	t.gameName = p.gameName
+	t.header = segment{
+		tokens:      rec.tokens[:len(rec.tokens):len(rec.tokens)],
+		diagnostics: rec.diagnostics[:len(rec.diagnostics):len(rec.diagnostics)],
+	}
+	if err != nil {
+		if _, ok := err.(ParserError); ok {
+			return nil
+		}
+		return err
+	}
+	t.body = true
+	return nil
+}
+
+// Edit applies the edit to the source of the tree and returns the resulting tree.
+// Only declarations touched by the edit are reparsed. The results of all other declarations are reused.
+// t is not modified.
+func (t *Tree) Edit(edit Edit) (*Tree, Update, error) {
+	tree, update, err := t.edit(edit)
+	if err != nil {
+		return nil, Update{}, err
+	}
+	c := tree.converter()
+	update.StartLine, update.StartColumn, _ = c.convert(update.StartLine, update.StartColumn)
+	update.EndLine, update.EndColumn, _ = c.convert(update.EndLine, update.EndColumn)
+	for i, o := range update.Objects {
+		update.Objects[i] = c.object(o)
+	}
+	for i, d := range update.Diagnostics {
+		update.Diagnostics[i] = c.diagnostic(d)
+	}
+	return tree, update, nil
+}
+
+// edit works like Edit, but all positions in the update are positions of the parser.
+func (t *Tree) edit(edit Edit) (*Tree, Update, error) {
+	c := t.converter()
+	var start, end pos
+	start.line, start.column = c.parserPos(edit.StartLine, edit.StartColumn)
+	end.line, end.column = c.parserPos(edit.EndLine, edit.EndColumn)
+	if end.before(start) {
+		return nil, Update{}, fmt.Errorf("invalid edit: end (%d:%d) before start (%d:%d)", edit.EndLine, edit.EndColumn, edit.StartLine, edit.StartColumn)
+	}
+	startOffset, ok := t.offset(start)
+	if !ok {
+		return nil, Update{}, fmt.Errorf("invalid edit: start (%d:%d) out of range", edit.StartLine, edit.StartColumn)
+	}
+	endOffset, ok := t.offset(end)
+	if !ok {
+		return nil, Update{}, fmt.Errorf("invalid edit: end (%d:%d) out of range", edit.EndLine, edit.EndColumn)
+	}
+	source := t.source[:startOffset] + edit.Text + t.source[endOffset:]
+
+	// first declaration which could be affected by the edit
+	first := -1
+	for i, s := range t.segments {
+		if start.before(s.start) {
+			break
+		}
+		first = i
+	}
+	// error recovery of the previous declaration stops at the first token of the edited one if it starts a declaration
+	if first > 0 && !t.segments[first].firstTokenEnd().before(start) {
+		first--
+	}
+
+	if !t.body {
+		tree, err := parseTree(source, t.config)
+		if err != nil {
+			return nil, Update{}, err
+		}
+		eof := tree.eofPos()
+		return tree, Update{
+			EndLine:     eof.line,
+			EndColumn:   eof.column,
+			Objects:     tree.parsedObjects(tree.segments),
+			Diagnostics: tree.diagnostics(),
+		}, nil
+	}
+
+	shift := newPosShift(end, start, edit.Text)
+	resume := make(map[pos]int)
+	for i := first + 1; i < len(t.segments); i++ {
+		if end.before(t.segments[i].start) {
+			resume[shift.apply(t.segments[i].start)] = i
+		}
+	}
+
+	tree := &Tree{
+		config:     t.config,
+		source:     source,
+		lineStarts: lineStarts(source),
+		cgeVersion: t.cgeVersion,
+		gameName:   t.gameName,
+		header:     t.header,
+		body:       true,
+	}
+
+	var reparseStart pos
+	var p *parser
+	var rec *recorder
+	reparseHeader := first <= 0
+	if reparseHeader {
+		// The header is reparsed as well because its end depends on the following tokens if it is malformed.
+		first = 0
+		tree.body = false
+		p, rec = tree.newParser(0, reparseStart)
+		err := tree.parseHeader(p, rec)
+		if err != nil {
+			return nil, Update{}, err
+		}
+		if !tree.body {
+			eof := tree.eofPos()
+			return tree, Update{
+				EndLine:     eof.line,
+				EndColumn:   eof.column,
+				Diagnostics: tree.diagnostics(),
+			}, nil
+		}
+	} else {
+		reparseStart = t.segments[first].start
+		p, rec = tree.newParser(t.lineStarts[reparseStart.line]+t.columnOffset(reparseStart), reparseStart)
+	}
+	reparsed, next := tree.parseSegments(p, rec, resume)
+	if next == -1 {
+		next = len(t.segments)
+	}
+
+	tree.segments = make([]segment, 0, first+len(reparsed)+len(t.segments)-next)
+	tree.segments = append(tree.segments, t.segments[:first]...)
+	tree.segments = append(tree.segments, reparsed...)
+	for _, s := range t.segments[next:] {
+		tree.segments = append(tree.segments, shift.segment(s))
+	}
+	tree.check()
+
+	update := Update{
+		StartLine:   reparseStart.line,
+		StartColumn: reparseStart.column,
+		Objects:     tree.parsedObjects(reparsed),
+		Reused:      len(tree.segments) - len(reparsed),
+	}
+	reparseEnd := tree.eofPos()
+	if next < len(t.segments) {
+		reparseEnd = shift.apply(t.segments[next].start)
+	}
+	update.EndLine, update.EndColumn = reparseEnd.line, reparseEnd.column
+	if reparseHeader {
+		update.Diagnostics = append(update.Diagnostics, tree.header.diagnostics...)
+	}
+	for _, s := range reparsed {
+		update.Diagnostics = append(update.Diagnostics, s.diagnostics...)
+	}
+	update.Diagnostics = append(update.Diagnostics, tree.checkDiagnostics...)
+
+	return tree, update, nil
+}
+
+func (t *Tree) Source() string {
+	return t.source
+}
+
+func (t *Tree) CGEVersion() string {
+	return t.cgeVersion
+}
+
+func (t *Tree) Tokens() []Token {
+	c := t.converter()
+	tokens := t.tokens()
+	for i, token := range tokens {
+		tokens[i] = c.token(token)
+	}
+	return tokens
+}
+
+func (t *Tree) tokens() []Token {
+	tokens := append([]Token{}, t.header.tokens...)
+	for _, s := range t.segments {
+		tokens = append(tokens, s.tokens...)
+	}
+	return tokens
+}
+
+// Objects returns all objects in the same order as Parse would send them.
+// In contrast to Parse, objects are returned even if the file contains errors.
+func (t *Tree) Objects() []Object {
+	c := t.converter()
+	objects := make([]Object, len(t.objects))
+	for i, o := range t.objects {
+		objects[i] = c.object(o)
+	}
+	return objects
+}
+
+// SemanticTokens classifies all identifiers in the parsed declarations.
+func (t *Tree) SemanticTokens() []SemanticToken {
+	objects := make([]Object, 0, len(t.segments))
+	accessed := make([]Token, 0)
+	for _, s := range t.segments {
+		objects = append(objects, s.objects...)
+		accessed = append(accessed, s.accessedTypeIdentifiers...)
+	}
+	c := t.converter()
+	tokens := classify(t.gameName, objects, accessed)
+	for i, token := range tokens {
+		tokens[i].Token = c.token(token.Token)
+	}
+	return tokens
+}
+
+// Diagnostics returns all diagnostics, which are not silenced by suppression comments, ordered by position like Parse.
+func (t *Tree) Diagnostics() []Diagnostic {
+	c := t.converter()
+	diagnostics := t.diagnostics()
+	for i, d := range diagnostics {
+		diagnostics[i] = c.diagnostic(d)
+	}
+	return diagnostics
+}
+
+// LineEnd returns the column of the end of the line without the line break.
+func (t *Tree) LineEnd(line int) int {
+	c := t.converter()
+	line -= c.base
+	_, column, _ := c.convert(line, t.lineLength(line))
+	return column
+}
+
+// converter converts positions of the parser to the configured position encoding.
+func (t *Tree) converter() *positionConverter {
+	return newPositionConverter(t.source, t.lineStarts, t.config)
+}
+
+func (t *Tree) diagnostics() []Diagnostic {
+	diagnostics := append([]Diagnostic{}, t.header.diagnostics...)
+	for _, s := range t.segments {
+		diagnostics = append(diagnostics, s.diagnostics...)
+	}
+	diagnostics = append(diagnostics, t.checkDiagnostics...)
+
+	comments := make([]suppressionComment, 0)
+	var previous Token
+	for _, token := range t.tokens() {
+		if isSuppressionComment(token) {
+			comments = append(comments, newSuppressionComment(token, previous))
+		} else {
+			previous = token
+		}
+	}
+	return finishDiagnostics(applySuppressions(comments, diagnostics, t.config), t.config)
+}
+
+func (t *Tree) parsedObjects(segments []segment) []Object {
+	objects := make([]Object, 0, len(segments))
+	for _, s := range segments {
+		objects = append(objects, s.objects...)
+	}
+	return objects
+}
+
+// newParser returns a parser, which starts reading the source of t at offset, which is located at start.
+func (t *Tree) newParser(offset int, start pos) (*parser, *recorder) {
+	// size, token and declaration limits are not applied, because segments are parsed separately
+	limits := Limits{
+		MaxNestingDepth:     t.config.Limits.MaxNestingDepth,
+		MaxIdentifierLength: t.config.Limits.MaxIdentifierLength,
+	}
+	s := newStringScanner(t.source[offset:], limits)
+	s.line = start.line
+	s.column = start.column
+
+	rec := &recorder{}
+	p := newParser(s, rec, Config{
+		IncludeComments: t.config.IncludeComments,
+		SendTokens:      true,
+		NoObjects:       true,
+		DisableWarnings: t.config.DisableWarnings,
+		BestEffort:      t.config.BestEffort,
+		AssumeVersion:   t.config.AssumeVersion,
+		Lint:            t.config.Lint,
+		Limits:          limits,
+	})
+	return p, rec
+}
+
+// parseSegments parses top-level declarations until EOF or until the next declaration starts at a position in resume.
+// It returns the parsed segments and the value of resume at the position, where parsing stopped (or -1 if EOF was reached).
+func (t *Tree) parseSegments(p *parser, rec *recorder, resume map[pos]int) ([]segment, int) {
+	segments := make([]segment, 0)
+	for p.scanner.peekToken(0).Type != TTEOF {
+		next := nextTokenStart(p.scanner)
+		if i, ok := resume[next]; ok {
+			return segments, i
+		}
+
+		tokenCount, diagnosticCount, objectCount, accessedCount := len(rec.tokens), len(rec.diagnostics), len(p.objects), len(p.accessedTypeIdentifiers)
+		p.topLevelDeclaration()
+		segments = append(segments, segment{
+			start:                   next,
+			tokens:                  rec.tokens[tokenCount:len(rec.tokens):len(rec.tokens)],
+			diagnostics:             rec.diagnostics[diagnosticCount:len(rec.diagnostics):len(rec.diagnostics)],
+			objects:                 p.objects[objectCount:len(p.objects):len(p.objects)],
+			accessedTypeIdentifiers: p.accessedTypeIdentifiers[accessedCount:len(p.accessedTypeIdentifiers):len(p.accessedTypeIdentifiers)],
+		})
+	}
+	return segments, -1
+}
+
+// nextTokenStart returns the start of the next token including the recoverable errors in front of it,
+// which can be located inside the token (e.g. invalid UTF-8 in a comment).
+func nextTokenStart(s *scanner) pos {
+	var start pos
+	for i := 0; ; i++ {
+		token := s.peekToken(i)
+		if p := (pos{token.Line, token.Column}); i == 0 || p.before(start) {
+			start = p
+		}
+		if !isRecoverableError(token) {
+			return start
+		}
+	}
+}
+
+func (t *Tree) check() {
+	rec := &recorder{}
+	p := newParser(nil, rec, Config{
+		DisableWarnings: t.config.DisableWarnings,
+		Lint:            t.config.Lint,
+	})
+	for _, s := range t.segments {
+		p.objects = append(p.objects, s.objects...)
+		p.accessedTypeIdentifiers = append(p.accessedTypeIdentifiers, s.accessedTypeIdentifiers...)
+	}
+	p.check()
+	t.objects = p.objects
+	t.checkDiagnostics = rec.diagnostics
+}
+
+// offset returns the byte offset of the position in the source.
+func (t *Tree) offset(p pos) (int, bool) {
+	if p.line < 0 || p.line >= len(t.lineStarts) || p.column < 0 {
+		return 0, false
+	}
+	column := t.columnOffset(p)
+	lineEnd := len(t.source)
+	if p.line+1 < len(t.lineStarts) {
+		lineEnd = t.lineStarts[p.line+1] - 1
+	}
+	if t.lineStarts[p.line]+column > lineEnd {
+		return 0, false
+	}
+	return t.lineStarts[p.line] + column, true
+}
+
+// columnOffset returns the byte offset of the position relative to the start of its line.
+// Like the scanner it does not count '\r' characters.
+func (t *Tree) columnOffset(p pos) int {
+	line := t.source[t.lineStarts[p.line]:]
+	offset := 0
+	for column := p.column; column > 0; {
+		if offset >= len(line) || line[offset] == '\n' {
+			return offset + column
+		}
+		r, size := utf8.DecodeRuneInString(line[offset:])
+		offset += size
+		if r != '\r' {
+			column--
+		}
+	}
+	return offset
+}
+
+func (t *Tree) eofPos() pos {
+	return pos{len(t.lineStarts) - 1, t.lineLength(len(t.lineStarts) - 1)}
+}
+
+// lineLength returns the number of columns in the line without the line break.
+func (t *Tree) lineLength(line int) int {
+	if line < 0 || line >= len(t.lineStarts) {
+		return 0
+	}
+	end := len(t.source)
+	if line+1 < len(t.lineStarts) {
+		end = t.lineStarts[line+1] - 1
+	}
+	text := t.source[t.lineStarts[line]:end]
+	return utf8.RuneCountInString(text) - strings.Count(text, "\r")
+}
+
+func lineStarts(source string) []int {
+	starts := make([]int, 1, strings.Count(source, "\n")+1)
+	for i := 0; i < len(source); i++ {
+		if source[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// posShift maps positions behind an edit in the old source to the new source.
+type posShift struct {
+	oldEnd pos
+	newEnd pos
+}
+
+func newPosShift(oldEnd, start pos, text string) posShift {
+	newEnd := start
+	text = strings.ReplaceAll(text, "\r", "")
+	if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+		newEnd.line += strings.Count(text, "\n")
+		newEnd.column = utf8.RuneCountInString(text[i+1:])
+	} else {
+		newEnd.column += utf8.RuneCountInString(text)
+	}
+	return posShift{
+		oldEnd: oldEnd,
+		newEnd: newEnd,
+	}
+}
+
+func (s posShift) apply(p pos) pos {
+	if p.line == s.oldEnd.line {
+		return pos{s.newEnd.line, p.column - s.oldEnd.column + s.newEnd.column}
+	}
+	return pos{p.line + s.newEnd.line - s.oldEnd.line, p.column}
+}
+
+func (s posShift) token(token Token) Token {
+	start := s.apply(pos{token.Line, token.Column})
+	end := s.apply(pos{token.EndLine, token.EndColumn})
+	token.Line, token.Column, token.EndLine, token.EndColumn = start.line, start.column, end.line, end.column
+	return token
+}
+
+func (s posShift) span(span Span) Span {
+	start := s.apply(pos{span.StartLine, span.StartColumn})
+	end := s.apply(pos{span.EndLine, span.EndColumn})
+	return Span{StartLine: start.line, StartColumn: start.column, EndLine: end.line, EndColumn: end.column}
+}
+
+func (s posShift) segment(seg segment) segment {
+	if s.oldEnd == s.newEnd || (seg.start.line > s.oldEnd.line && s.oldEnd.line == s.newEnd.line) {
+		return seg
+	}
+
+	shifted := segment{
+		start:                   s.apply(seg.start),
+		tokens:                  make([]Token, len(seg.tokens)),
+		diagnostics:             make([]Diagnostic, len(seg.diagnostics)),
+		objects:                 make([]Object, len(seg.objects)),
+		accessedTypeIdentifiers: make([]Token, len(seg.accessedTypeIdentifiers)),
+	}
+	for i, token := range seg.tokens {
+		shifted.tokens[i] = s.token(token)
+	}
+	for i, d := range seg.diagnostics {
+		shifted.diagnostics[i] = s.diagnostic(d)
+	}
+	for i, o := range seg.objects {
+		o.Name = s.token(o.Name)
+		o.Span = s.span(o.Span)
+		properties := make([]Property, len(o.Properties))
+		for j, p := range o.Properties {
+			p.NameToken = s.token(p.NameToken)
+			p.Type = s.propertyType(p.Type)
+			p.Span = s.span(p.Span)
+			properties[j] = p
+		}
+		o.Properties = properties
+		shifted.objects[i] = o
+	}
+	for i, token := range seg.accessedTypeIdentifiers {
+		shifted.accessedTypeIdentifiers[i] = s.token(token)
+	}
+	return shifted
+}
+
+func (s posShift) diagnostic(d Diagnostic) Diagnostic {
+	start := s.apply(pos{d.StartLine, d.StartColumn})
+	end := s.apply(pos{d.EndLine, d.EndColumn})
+	d.StartLine, d.StartColumn, d.EndLine, d.EndColumn = start.line, start.column, end.line, end.column
+	if d.Related != nil {
+		related := make([]RelatedLocation, len(d.Related))
+		for i, r := range d.Related {
+			start := s.apply(pos{r.StartLine, r.StartColumn})
+			end := s.apply(pos{r.EndLine, r.EndColumn})
+			r.StartLine, r.StartColumn, r.EndLine, r.EndColumn = start.line, start.column, end.line, end.column
+			related[i] = r
+		}
+		d.Related = related
+	}
+	if d.Fixes != nil {
+		fixes := make([]Fix, len(d.Fixes))
+		for i, f := range d.Fixes {
+			edits := make([]Edit, len(f.Edits))
+			for j, e := range f.Edits {
+				start := s.apply(pos{e.StartLine, e.StartColumn})
+				end := s.apply(pos{e.EndLine, e.EndColumn})
+				e.StartLine, e.StartColumn, e.EndLine, e.EndColumn = start.line, start.column, end.line, end.column
+				edits[j] = e
+			}
+			f.Edits = edits
+			fixes[i] = f
+		}
+		d.Fixes = fixes
+	}
+	return d
+}
+
+func (s posShift) propertyType(t *PropertyType) *PropertyType {
+	if t == nil {
+		return nil
+	}
+	return &PropertyType{
+		Token:   s.token(t.Token),
+		Generic: s.propertyType(t.Generic),
+		Span:    s.span(t.Span),
+	}
+}
+
+type recorder struct {
+	cgeVersion  string
+	tokens      []Token
+	diagnostics []Diagnostic
+}
+
+func (r *recorder) SendMetadata(version string) error {
+	r.cgeVersion = version
+	return nil
+}
+
+func (r *recorder) SendDiagnostic(diagnostic Diagnostic) error {
+	r.diagnostics = append(r.diagnostics, diagnostic)
+	return nil
+}
+
+func (r *recorder) SendToken(token Token) error {
+	if token.Type == TTEOF {
+		return nil
+	}
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *recorder) SendSemanticToken(token SemanticToken) error {
+	return nil
+}
+
+func (r *recorder) SendObject(object Object) error {
+	return nil
+}
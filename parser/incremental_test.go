@@ -0,0 +1,254 @@
+package parser
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const incrementalSource = `cge 0.5
+
+// The config.
+config {
+	max_players: int
+}
+
+// Sent when a player joins.
+event joined {
+	player: player,
+	// all players
+	players: list<player>,
+}
+
+command move {
+	direction: direction, pos: type position { x: float, y: float }
+}
+
+/* A player. */
+type player {
+	name: string,
+	color: enum color { red, green, blue },
+	scores: map<int64>
+}
+
+enum direction {
+	up, down, left, right
+}
+`
+
+func TestTreeEdit(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		edit       Edit
+		wantReused bool
+	}{
+		{"insert property", incrementalSource, Edit{StartLine: 11, StartColumn: 23, EndLine: 11, EndColumn: 23, Text: "\n\tscore: int,"}, true},
+		{"rename type", incrementalSource, Edit{StartLine: 19, StartColumn: 5, EndLine: 19, EndColumn: 11, Text: "user"}, true},
+		{"delete closing brace", incrementalSource, Edit{StartLine: 12, StartColumn: 0, EndLine: 12, EndColumn: 1, Text: ""}, true},
+		{"open block comment", incrementalSource, Edit{StartLine: 8, StartColumn: 0, EndLine: 8, EndColumn: 0, Text: "/*"}, false},
+		{"close block comment", "cge 0.5\n/* event a {}\nevent b {}\n", Edit{StartLine: 1, StartColumn: 13, EndLine: 1, EndColumn: 13, Text: "*/"}, false},
+		{"same line declarations", "cge 0.5\nevent a {} event b { x: int } type c {}\n", Edit{StartLine: 1, StartColumn: 6, EndLine: 1, EndColumn: 7, Text: "long_name"}, true},
+		{"join declarations", incrementalSource, Edit{StartLine: 12, StartColumn: 1, EndLine: 14, EndColumn: 0, Text: " "}, true},
+		{"multi-line replacement", incrementalSource, Edit{StartLine: 16, StartColumn: 1, EndLine: 22, EndColumn: 2, Text: "\n}\n\nevent left {\n\tx: int"}, true},
+		{"edit header", incrementalSource, Edit{StartLine: 0, StartColumn: 4, EndLine: 0, EndColumn: 7, Text: "0.4"}, false},
+		{"append declaration", incrementalSource, Edit{StartLine: 28, StartColumn: 0, EndLine: 28, EndColumn: 0, Text: "event left { player: player }\n"}, true},
+		{"duplicate declaration", incrementalSource, Edit{StartLine: 25, StartColumn: 5, EndLine: 25, EndColumn: 14, Text: "player"}, true},
+		{"undefined type", incrementalSource, Edit{StartLine: 9, StartColumn: 9, EndLine: 9, EndColumn: 15, Text: "players"}, true},
+		{"unicode comment", "cge 0.5\n// äöü 😀\nevent a { x: int }\nevent b {}\n", Edit{StartLine: 1, StartColumn: 7, EndLine: 1, EndColumn: 8, Text: "🎉🎉"}, true},
+		{"crlf", "cge 0.5\r\nevent a {\r\n\tx: int\r\n}\r\nevent b {}\r\n", Edit{StartLine: 2, StartColumn: 1, EndLine: 2, EndColumn: 2, Text: "y"}, true},
+		{"missing header", "event a { x: int }\nevent b {}\n", Edit{StartLine: 1, StartColumn: 6, EndLine: 1, EndColumn: 7, Text: "c"}, true},
+		{"malformed header", "cge 0.\nconfig {}\nevent a {}\n", Edit{StartLine: 1, StartColumn: 0, EndLine: 1, EndColumn: 1, Text: "x"}, true},
+		{"syntax error", incrementalSource, Edit{StartLine: 21, StartColumn: 6, EndLine: 21, EndColumn: 7, Text: ""}, true},
+		{"invalid utf-8 in doc comment", "cge 0.5\nevent a {}\n// doc \xff\nevent b {}\n", Edit{StartLine: 2, StartColumn: 3, EndLine: 2, EndColumn: 3, Text: "x"}, false},
+		{"uppercase declaration name", "cge 0.5\nevent a {}\nevent Foo {}\nevent b {}\n", Edit{StartLine: 1, StartColumn: 9, EndLine: 1, EndColumn: 10, Text: ""}, false},
+		{"suppression comment", "cge 0.5\nevent a { x: int }\nevent b { y: int }\n// cge:ignore-file type-alias\n", Edit{StartLine: 2, StartColumn: 0, EndLine: 2, EndColumn: 0, Text: "// cge:ignore type-alias\n"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, err := ParseTree(strings.NewReader(tt.source), Config{IncludeComments: true})
+			if err != nil {
+				t.Fatalf("ParseTree() error = %v", err)
+			}
+			got, update, err := tree.Edit(tt.edit)
+			if err != nil {
+				t.Fatalf("Edit() error = %v", err)
+			}
+			want, err := ParseTree(strings.NewReader(applyEdit(t, tt.source, tt.edit)), Config{IncludeComments: true})
+			if err != nil {
+				t.Fatalf("ParseTree() error = %v", err)
+			}
+			assertTreesEqual(t, got, want)
+			if tt.wantReused && update.Reused == 0 {
+				t.Errorf("Edit() reused no declarations")
+			}
+			if tree.Source() != tt.source {
+				t.Errorf("Edit() modified the original tree")
+			}
+		})
+	}
+}
+
+func TestTreePositionEncoding(t *testing.T) {
+	source := "cge 0.5\r\n/* 😀 */ event a { b: strin }\n"
+	config := Config{PositionEncoding: PositionUTF16, OneBased: true}
+	tree, err := ParseTree(strings.NewReader(source), config)
+	if err != nil {
+		t.Fatalf("ParseTree() error = %v", err)
+	}
+	rec := &recorder{}
+	err = Parse(strings.NewReader(source), rec, config)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(tree.Diagnostics(), rec.diagnostics) {
+		t.Fatalf("Diagnostics() = %+v, want %+v", tree.Diagnostics(), rec.diagnostics)
+	}
+	if got, want := tree.LineEnd(2), 30; got != want {
+		t.Errorf("LineEnd(2) = %d, want %d", got, want)
+	}
+
+	d := tree.Diagnostics()[0]
+	tree, update, err := tree.Edit(Edit{StartLine: d.StartLine, StartColumn: d.StartColumn, EndLine: d.EndLine, EndColumn: d.EndColumn, Text: "string"})
+	if err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	if want := "cge 0.5\r\n/* 😀 */ event a { b: string }\n"; tree.Source() != want {
+		t.Errorf("Source() = %q, want %q", tree.Source(), want)
+	}
+	if len(tree.Diagnostics()) != 0 {
+		t.Errorf("Diagnostics() = %v, want none", diagnosticSummary(tree.Diagnostics()))
+	}
+	if update.EndLine != 3 || update.EndColumn != 1 {
+		t.Errorf("Update ends at %d:%d, want 3:1", update.EndLine, update.EndColumn)
+	}
+}
+
+func TestTreeEditInvalid(t *testing.T) {
+	tree, err := ParseTree(strings.NewReader(incrementalSource), Config{})
+	if err != nil {
+		t.Fatalf("ParseTree() error = %v", err)
+	}
+	edits := []Edit{
+		{StartLine: 2, StartColumn: 0, EndLine: 1, EndColumn: 0},
+		{StartLine: 100, StartColumn: 0, EndLine: 100, EndColumn: 0},
+		{StartLine: 0, StartColumn: 50, EndLine: 0, EndColumn: 50},
+		{StartLine: -1, StartColumn: 0, EndLine: 0, EndColumn: 0},
+	}
+	for _, e := range edits {
+		if _, _, err := tree.Edit(e); err == nil {
+			t.Errorf("Edit(%+v) expected error", e)
+		}
+	}
+}
+
+func TestTreeEditRandom(t *testing.T) {
+	snippets := []string{"", "}", "{", ",", ":", "<", ">", " ", "\n", "event", "type x {", "enum e { a }", "a: int", "list<", "/*", "*/", "// c\n", "player", "ä", "\xff", "Foo", "/", "\uFEFF"}
+	rng := rand.New(rand.NewSource(1))
+
+	source := incrementalSource
+	tree, err := ParseTree(strings.NewReader(source), Config{IncludeComments: true})
+	if err != nil {
+		t.Fatalf("ParseTree() error = %v", err)
+	}
+	for i := 0; i < 500; i++ {
+		start := rng.Intn(utf8.RuneCountInString(source) + 1)
+		end := start + rng.Intn(8)
+		if end > utf8.RuneCountInString(source) {
+			end = start
+		}
+		edit := Edit{Text: snippets[rng.Intn(len(snippets))]}
+		edit.StartLine, edit.StartColumn = runePos(source, start)
+		edit.EndLine, edit.EndColumn = runePos(source, end)
+
+		tree, _, err = tree.Edit(edit)
+		if err != nil {
+			t.Fatalf("step %d: Edit(%+v) error = %v", i, edit, err)
+		}
+		source = applyEdit(t, source, edit)
+		want, err := ParseTree(strings.NewReader(source), Config{IncludeComments: true})
+		if err != nil {
+			t.Fatalf("step %d: ParseTree() error = %v", i, err)
+		}
+		if !assertTreesEqual(t, tree, want) {
+			t.Fatalf("step %d: Edit(%+v) differs from full reparse of:\n%s", i, edit, source)
+		}
+	}
+}
+
+func assertTreesEqual(t *testing.T, got, want *Tree) bool {
+	t.Helper()
+	ok := true
+	if got.Source() != want.Source() {
+		t.Errorf("Source() = %q, want %q", got.Source(), want.Source())
+		ok = false
+	}
+	if !reflect.DeepEqual(got.Tokens(), want.Tokens()) {
+		t.Errorf("Tokens() = %v, want %v", got.Tokens(), want.Tokens())
+		ok = false
+	}
+	if !reflect.DeepEqual(got.Objects(), want.Objects()) {
+		t.Errorf("Objects() = %v, want %v", got.Objects(), want.Objects())
+		ok = false
+	}
+	if !reflect.DeepEqual(got.SemanticTokens(), want.SemanticTokens()) {
+		t.Errorf("SemanticTokens() = %v, want %v", got.SemanticTokens(), want.SemanticTokens())
+		ok = false
+	}
+	if !reflect.DeepEqual(got.Diagnostics(), want.Diagnostics()) {
+		t.Errorf("Diagnostics() = %v, want %v", got.Diagnostics(), want.Diagnostics())
+		ok = false
+	}
+	return ok
+}
+
+// applyEdit applies the edit to source without using the Tree implementation.
+func applyEdit(t *testing.T, source string, edit Edit) string {
+	t.Helper()
+	start := runeOffset(source, edit.StartLine, edit.StartColumn)
+	end := runeOffset(source, edit.EndLine, edit.EndColumn)
+	if start < 0 || end < 0 {
+		t.Fatalf("invalid edit %+v", edit)
+	}
+	return source[:start] + edit.Text + source[end:]
+}
+
+func runeOffset(source string, line, column int) int {
+	l, c := 0, 0
+	for i, r := range source {
+		if l == line && c == column {
+			return i
+		}
+		if r == '\n' {
+			if l == line {
+				return -1
+			}
+			l++
+			c = 0
+		} else if r != '\r' {
+			c++
+		}
+	}
+	if l == line && c == column {
+		return len(source)
+	}
+	return -1
+}
+
+func runePos(source string, runeIndex int) (line, column int) {
+	for i, r := range []rune(source) {
+		if i == runeIndex {
+			break
+		}
+		if r == '\n' {
+			line++
+			column = 0
+		} else if r != '\r' {
+			column++
+		}
+	}
+	return line, column
+}
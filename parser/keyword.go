@@ -0,0 +1,45 @@
+package parser
+
+// keywordTypes maps all reserved words to the token types the scanner produces for them.
+var keywordTypes = map[string]TokenType{
+	"name":    TTGameName,
+	"version": TTCGEVersion,
+	"cge":     TTCGEVersion,
+	"config":  TTConfig,
+	"command": TTCommand,
+	"event":   TTEvent,
+	"type":    TTType,
+	"enum":    TTEnum,
+	"string":  TTString,
+	"bool":    TTBool,
+	"int":     TTInt32,
+	"int32":   TTInt32,
+	"int64":   TTInt64,
+	"float":   TTFloat64,
+	"float32": TTFloat32,
+	"float64": TTFloat64,
+	"list":    TTList,
+	"map":     TTMap,
+}
+
+var declarationKeywords = []string{"config", "command", "event", "type", "enum"}
+
+// primitiveTypeNames contains the canonical names of all primitive types without generics.
+var primitiveTypeNames = []string{"string", "bool", "int32", "int64", "float32", "float64"}
+
+// IsKeyword reports whether name is a reserved word, which cannot be used as an identifier.
+// Reserved words are the metadata and declaration keywords and the names of all primitive types including aliases.
+func IsKeyword(name string) bool {
+	_, ok := keywordTypes[name]
+	return ok
+}
+
+// DeclarationKeywords returns the keywords which start a declaration.
+func DeclarationKeywords() []string {
+	return append([]string(nil), declarationKeywords...)
+}
+
+// PrimitiveTypes returns the canonical names of all primitive types including the generics 'list' and 'map'.
+func PrimitiveTypes() []string {
+	return append(append([]string(nil), primitiveTypeNames...), "list", "map")
+}
@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeywords(t *testing.T) {
+	for keyword, want := range keywordTypes {
+		s := newScanner(strings.NewReader(keyword), Limits{})
+		if got := s.nextToken(); got.Type != want {
+			t.Errorf("scanned %q as %d, want %d", keyword, got.Type, want)
+		}
+	}
+	for _, name := range append(DeclarationKeywords(), PrimitiveTypes()...) {
+		if !IsKeyword(name) {
+			t.Errorf("IsKeyword(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"player", "names", "integer", ""} {
+		if IsKeyword(name) {
+			t.Errorf("IsKeyword(%q) = true, want false", name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package parser
+
+import "fmt"
+
+// defaultMaxTypeDepth protects the recursive descent parser against stack overflows.
+const defaultMaxTypeDepth = 256
+
+// Limits restricts the resources used for parsing untrusted input.
+// A zero value disables the respective limit unless stated otherwise.
+type Limits struct {
+	// MaxBytes is the maximum size of the input in bytes.
+	MaxBytes int
+	// MaxTokens is the maximum number of tokens including comments.
+	MaxTokens int
+	// MaxNestingDepth is the maximum number of nested generics and inline declarations in a property type (default: 256).
+	MaxNestingDepth int
+	// MaxDeclarations is the maximum number of declarations including inline declarations.
+	MaxDeclarations int
+	// MaxIdentifierLength is the maximum length of an identifier in characters.
+	MaxIdentifierLength int
+}
+
+func (l Limits) maxNestingDepth() int {
+	if l.MaxNestingDepth <= 0 {
+		return defaultMaxTypeDepth
+	}
+	return l.MaxNestingDepth
+}
+
+// exceedLimit stops scanning. All following tokens are EOF.
+// The error is reported by the parser, because it would be skipped like any other token during error recovery.
+func (s *scanner) exceedLimit(span Span, message string) {
+	if s.limitError != nil {
+		return
+	}
+	s.limitError = &Token{
+		Line:      span.StartLine,
+		Column:    span.StartColumn,
+		EndLine:   span.EndLine,
+		EndColumn: span.EndColumn,
+		Type:      TTError,
+		Lexeme:    message,
+		errorCode: CodeLimitExceeded,
+	}
+}
+
+// checkLimits reports an exceeded size, token or declaration limit.
+// It returns true if parsing has to stop.
+func (p *parser) checkLimits() bool {
+	if p.limitExceeded {
+		return true
+	}
+	if p.scanner.limitError != nil {
+		p.limitExceeded = true
+		p.report(*p.scanner.limitError, CodeLimitExceeded, p.scanner.limitError.Lexeme, false, nil, nil)
+		return true
+	}
+	if max := p.config.Limits.MaxDeclarations; max > 0 && len(p.objects) > max {
+		p.limitExceeded = true
+		p.report(p.objects[max].Name, CodeLimitExceeded, fmt.Sprintf("too many declarations (maximum: %d)", max), false, nil, nil)
+		return true
+	}
+	return false
+}
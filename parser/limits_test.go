@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	deep := "cge 0.5\nevent e { x: " + strings.Repeat("list<", 100000) + "string" + strings.Repeat(">", 100000) + " }\n"
+	tests := []struct {
+		name   string
+		source string
+		limits Limits
+		want   []string
+	}{
+		{"bytes", "cge 0.5\nevent a { x: string }\n", Limits{MaxBytes: 20}, []string{"1:12 CGE0033"}},
+		{"tokens", "cge 0.5\nevent a { x: string }\nevent b {}\n", Limits{MaxTokens: 9}, []string{"2:0 CGE0033"}},
+		{"tokens not exceeded", "cge 0.5\nevent a { x: string }\n", Limits{MaxTokens: 9}, []string{}},
+		{"nesting depth", "cge 0.5\nevent a { x: list<map<type b { y: list<string> }>>, z: string }\n", Limits{MaxNestingDepth: 2}, []string{"1:34 CGE0033"}},
+		{"default nesting depth", deep, Limits{}, []string{"1:1298 CGE0033"}},
+		{"declarations", "cge 0.5\nevent a { x: type b {} }\nevent c {}\nevent d {}\n", Limits{MaxDeclarations: 2}, []string{"2:6 CGE0033"}},
+		{"identifier length", "cge 0.5\nevent abcdef { abc: string }\n", Limits{MaxIdentifierLength: 5}, []string{"1:6 CGE0033"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			err := Parse(strings.NewReader(tt.source), rec, Config{Limits: tt.limits})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := diagnosticSummary(rec.diagnostics); !equalStrings(got, tt.want) {
+				t.Errorf("Parse() diagnostics = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+type LintSeverity int
+
+const (
+	LintOff LintSeverity = iota
+	LintInfo
+	LintWarning
+	LintError
+)
+
+func (s LintSeverity) String() string {
+	switch s {
+	case LintOff:
+		return "off"
+	case LintInfo:
+		return "info"
+	case LintWarning:
+		return "warning"
+	case LintError:
+		return "error"
+	}
+	return "unknown"
+}
+
+func ParseLintSeverity(severity string) (LintSeverity, bool) {
+	for s := LintOff; s <= LintError; s++ {
+		if s.String() == severity {
+			return s, true
+		}
+	}
+	return LintOff, false
+}
+
+const defaultDeepNestingThreshold = 3
+
+// LintConfig configures the lint rules. The zero value enables all rules with their default severities.
+type LintConfig struct {
+	// Severities overrides the default severities of the lint rules.
+	Severities map[DiagnosticCode]LintSeverity
+	// MaxNestingDepth is the maximum nesting depth of property types allowed by deep-nesting (default: 3).
+	MaxNestingDepth int
+}
+
+// defaultLintSeverities contains all lint rules with their default severities.
+var defaultLintSeverities = map[DiagnosticCode]LintSeverity{
+	CodeTypeAlias:        LintInfo,
+	CodeUnusedType:       LintWarning,
+	CodeEmptyEvent:       LintOff,
+	CodeMissingDoc:       LintOff,
+	CodeNamingConvention: LintWarning,
+	CodeEnumMemberClash:  LintWarning,
+	CodeDeepNesting:      LintWarning,
+}
+
+// lintChecks contains the rules, which are checked after parsing, in the order they are run.
+var lintChecks = []struct {
+	code  DiagnosticCode
+	check func(p *parser)
+}{
+	{CodeUnusedType, (*parser).lintUnusedTypes},
+	{CodeEmptyEvent, (*parser).lintEmptyEvents},
+	{CodeMissingDoc, (*parser).lintMissingDocs},
+	{CodeNamingConvention, (*parser).lintNamingConvention},
+	{CodeEnumMemberClash, (*parser).lintEnumMemberClashes},
+	{CodeDeepNesting, (*parser).lintDeepNesting},
+}
+
+// LintRules returns the codes of all lint rules with their default severities.
+func LintRules() map[DiagnosticCode]LintSeverity {
+	rules := make(map[DiagnosticCode]LintSeverity, len(defaultLintSeverities))
+	for code, severity := range defaultLintSeverities {
+		rules[code] = severity
+	}
+	return rules
+}
+
+// Set changes the severity of a rule. The rule can be specified by name (e.g. 'unused-type') or code.
+func (c *LintConfig) Set(rule, severity string) error {
+	code, ok := ParseDiagnosticCode(rule)
+	if _, isRule := defaultLintSeverities[code]; !ok || !isRule {
+		return fmt.Errorf("unknown lint rule '%s'", rule)
+	}
+	s, ok := ParseLintSeverity(severity)
+	if !ok {
+		return fmt.Errorf("invalid severity '%s' for lint rule '%s': expected off, info, warning or error", severity, rule)
+	}
+	if c.Severities == nil {
+		c.Severities = make(map[DiagnosticCode]LintSeverity)
+	}
+	c.Severities[code] = s
+	return nil
+}
+
+func (c LintConfig) severity(code DiagnosticCode) LintSeverity {
+	if s, ok := c.Severities[code]; ok {
+		return s
+	}
+	return defaultLintSeverities[code]
+}
+
+func (c LintConfig) deepNestingThreshold() int {
+	if c.MaxNestingDepth <= 0 {
+		return defaultDeepNestingThreshold
+	}
+	return c.MaxNestingDepth
+}
+
+// ParseLintConfig reads a JSON lint config file like:
+//
+//	{
+//		"rules": { "unused-type": "error", "missing-doc": "warning" },
+//		"maxNestingDepth": 4
+//	}
+func ParseLintConfig(input io.Reader) (LintConfig, error) {
+	var file struct {
+		Rules           map[string]string `json:"rules"`
+		MaxNestingDepth int               `json:"maxNestingDepth"`
+	}
+	decoder := json.NewDecoder(input)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&file)
+	if err != nil {
+		return LintConfig{}, fmt.Errorf("invalid lint config: %w", err)
+	}
+	config := LintConfig{
+		MaxNestingDepth: file.MaxNestingDepth,
+	}
+	for rule, severity := range file.Rules {
+		err = config.Set(rule, severity)
+		if err != nil {
+			return LintConfig{}, fmt.Errorf("invalid lint config: %w", err)
+		}
+	}
+	return config, nil
+}
+
+func (p *parser) runLintRules() {
+	for _, r := range lintChecks {
+		if p.config.Lint.severity(r.code) != LintOff {
+			r.check(p)
+		}
+	}
+}
+
+// lint reports a lint result with the configured severity.
+func (p *parser) lint(span Span, code DiagnosticCode, message string, related []RelatedLocation, fixes ...Fix) {
+	diagnosticType := DiagnosticInfo
+	switch p.config.Lint.severity(code) {
+	case LintOff:
+		return
+	case LintWarning:
+		diagnosticType = DiagnosticWarning
+	case LintError:
+		diagnosticType = DiagnosticError
+		p.hadError = true
+	}
+	if diagnosticType != DiagnosticError && p.config.DisableWarnings {
+		return
+	}
+	p.sent(p.out.SendDiagnostic(Diagnostic{
+		Type:        diagnosticType,
+		Code:        code,
+		Message:     message,
+		StartLine:   span.StartLine,
+		StartColumn: span.StartColumn,
+		EndLine:     span.EndLine,
+		EndColumn:   span.EndColumn,
+		Related:     related,
+		Fixes:       fixes,
+	}))
+}
+
+func (p *parser) lintUnusedTypes() {
+	used := make(map[string]bool)
+	for _, o := range p.objects {
+		// the broken part of an incomplete declaration might reference the type
+		for _, name := range o.possibleReferences {
+			if name != o.Name.Lexeme {
+				used[name] = true
+			}
+		}
+		for _, prop := range o.Properties {
+			for t := prop.Type; t != nil; t = t.Generic {
+				if t.Token.Type == TTIdentifier && t.Token.Lexeme != o.Name.Lexeme {
+					used[t.Token.Lexeme] = true
+				}
+			}
+		}
+	}
+	for _, o := range p.objects {
+		if (o.Type == TTType || o.Type == TTEnum) && !o.inline && !used[o.Name.Lexeme] {
+			p.lint(o.Name.Span(), CodeUnusedType, fmt.Sprintf("%s '%s' is never used", o.Keyword(), o.Name.Lexeme), nil)
+		}
+	}
+}
+
+func (p *parser) lintEmptyEvents() {
+	for _, o := range p.objects {
+		if o.Type == TTEvent && !o.Incomplete && len(o.Properties) == 0 {
+			p.lint(o.Span, CodeEmptyEvent, fmt.Sprintf("event '%s' has no properties", o.Name.Lexeme), nil)
+		}
+	}
+}
+
+func (p *parser) lintMissingDocs() {
+	for _, o := range p.objects {
+		if o.Type != TTConfig && !o.inline && !o.documented {
+			p.lint(o.Name.Span(), CodeMissingDoc, fmt.Sprintf("%s '%s' has no doc comment", o.Keyword(), o.Name.Lexeme), nil)
+		}
+	}
+}
+
+var snakeCaseRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// lintNamingConvention ignores names with uppercase letters, which are already reported by the scanner.
+func (p *parser) lintNamingConvention() {
+	for _, o := range p.objects {
+		if o.Type != TTConfig && !snakeCaseRegex.MatchString(o.Name.Lexeme) && !hasUpper(o.Name.Lexeme) {
+			p.lint(o.Name.Span(), CodeNamingConvention, fmt.Sprintf("%s name '%s' is not snake_case", o.Keyword(), o.Name.Lexeme), nil)
+		}
+		for _, prop := range o.Properties {
+			name := prop.Name
+			if snakeCaseRegex.MatchString(name) || hasUpper(name) {
+				continue
+			}
+			message := fmt.Sprintf("property name '%s' is not snake_case", name)
+			if o.Type == TTEnum {
+				message = fmt.Sprintf("enum member '%s' is not snake_case", name)
+			}
+			if fixed := toSnakeCase(name); snakeCaseRegex.MatchString(fixed) {
+				p.lint(prop.NameToken.Span(), CodeNamingConvention, message, nil, replaceFix(fmt.Sprintf("Rename to '%s'", fixed), prop.NameToken, fixed))
+			} else {
+				p.lint(prop.NameToken.Span(), CodeNamingConvention, message, nil)
+			}
+		}
+	}
+}
+
+func hasUpper(name string) bool {
+	return strings.ToLower(name) != name
+}
+
+// toSnakeCase removes leading, trailing and repeated underscores.
+func toSnakeCase(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_'
+	})
+	return strings.ToLower(strings.Join(words, "_"))
+}
+
+func (p *parser) lintEnumMemberClashes() {
+	for _, o := range p.objects {
+		if o.Type != TTEnum {
+			continue
+		}
+		for _, member := range o.Properties {
+			if s, ok := p.symbols[member.Name]; ok {
+				keyword := "type"
+				if s.Kind == TTEnum {
+					keyword = "enum"
+				}
+				p.lint(member.NameToken.Span(), CodeEnumMemberClash, fmt.Sprintf("enum member '%s' has the same name as %s '%s'", member.Name, keyword, s.Name.Lexeme), []RelatedLocation{
+					relatedToken(s.Name, fmt.Sprintf("%s '%s' declared here", keyword, s.Name.Lexeme)),
+				})
+			}
+		}
+	}
+}
+
+func (p *parser) lintDeepNesting() {
+	inline := make(map[pos]Object)
+	for _, o := range p.objects {
+		if o.inline {
+			inline[pos{o.Name.Line, o.Name.Column}] = o
+		}
+	}
+	max := p.config.Lint.deepNestingThreshold()
+	for _, o := range p.objects {
+		if o.inline {
+			continue
+		}
+		for _, prop := range o.Properties {
+			if depth := nestingDepth(prop.Type, inline); depth > max {
+				p.lint(prop.Type.Span, CodeDeepNesting, fmt.Sprintf("type of '%s' is nested %d levels deep (maximum: %d)", prop.Name, depth, max), nil)
+			}
+		}
+	}
+}
+
+// nestingDepth returns the number of nested generics and inline declarations in the property type.
+func nestingDepth(t *PropertyType, inline map[pos]Object) int {
+	if t == nil {
+		return 0
+	}
+	if t.Generic != nil {
+		return 1 + nestingDepth(t.Generic, inline)
+	}
+	o, ok := inline[pos{t.Token.Line, t.Token.Column}]
+	if !ok {
+		return 0
+	}
+	depth := 0
+	for _, prop := range o.Properties {
+		if d := nestingDepth(prop.Type, inline); d > depth {
+			depth = d
+		}
+	}
+	return 1 + depth
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		rules  map[string]string
+		want   []string
+	}{
+		{"unused type", "cge 0.5\ntype a {}\ntype b { x: list<b> }\ntype c { y: a }\nevent e { z: c }\n", nil, []string{"2:5 CGE0025"}},
+		{"unused type next to incomplete declaration", "cge 0.5\ntype a {}\ntype b {}\nevent e { x: b, y: }\n", nil, []string{"1:5 CGE0025", "3:19 CGE0014"}},
+		{"type used in skipped tokens", "cge 0.5\ntype a {}\ntype b {}\nevent e { x: list<int32 a> }\n", nil, []string{"2:5 CGE0025", "3:24 CGE0015"}},
+		{"type used in dropped property", "cge 0.5\ntype a {}\ntype b {}\nevent e { x: map<b }\n", nil, []string{"1:5 CGE0025", "3:19 CGE0015"}},
+		{"unused incomplete type", "cge 0.5\ntype a {}\ntype b { x: int32, y: }\nevent e { z: a }\n", nil, []string{"2:22 CGE0014", "2:5 CGE0025"}},
+		{"empty event", "cge 0.5\nevent a {}\n", map[string]string{"empty-event": "warning"}, []string{"1:0 CGE0026"}},
+		{"missing doc", "cge 0.5\n// A.\nevent a {}\nevent b {}\n", map[string]string{"missing-doc": "info"}, []string{"3:6 CGE0027"}},
+		{"naming convention", "cge 0.5\nevent a__b { _c: string, d_: string }\n", nil, []string{"1:13 CGE0028", "1:25 CGE0028", "1:6 CGE0028"}},
+		{"enum member clash", "cge 0.5\nenum a { b, c }\ntype b {}\nevent e { x: a, y: b }\n", nil, []string{"1:9 CGE0029"}},
+		{"deep nesting", "cge 0.5\nevent e { x: list<list<list<int32>>>, y: map<type a { z: list<list<int32>> }> }\n", nil, []string{"1:41 CGE0030"}},
+		{"disabled", "cge 0.5\ntype a {}\n", map[string]string{"unused-type": "off"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			for rule, severity := range tt.rules {
+				if err := config.Lint.Set(rule, severity); err != nil {
+					t.Fatal(err)
+				}
+			}
+			rec := &recorder{}
+			err := Parse(strings.NewReader(tt.source), rec, config)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := diagnosticSummary(rec.diagnostics); !equalStrings(got, tt.want) {
+				t.Errorf("Parse() diagnostics = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLintConfig(t *testing.T) {
+	config, err := ParseLintConfig(strings.NewReader(`{"rules": {"unused-type": "error", "CGE0027": "warning"}, "maxNestingDepth": 5}`))
+	if err != nil {
+		t.Fatalf("ParseLintConfig() error = %v", err)
+	}
+	if config.severity(CodeUnusedType) != LintError || config.severity(CodeMissingDoc) != LintWarning || config.severity(CodeNamingConvention) != LintWarning || config.deepNestingThreshold() != 5 {
+		t.Errorf("ParseLintConfig() = %+v", config)
+	}
+
+	_, err = ParseLintConfig(strings.NewReader(`{"rules": {"undefined-type": "off"}}`))
+	if err == nil {
+		t.Error("ParseLintConfig() accepted a diagnostic which is not a lint rule")
+	}
+}
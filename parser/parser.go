@@ -1,9 +1,10 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -11,11 +12,31 @@ import (
 )
 
 type Config struct {
-	IncludeComments bool
-	OnlyMetadata    bool
-	SendTokens      bool
-	NoObjects       bool
-	DisableWarnings bool
+	IncludeComments    bool
+	OnlyMetadata       bool
+	SendTokens         bool
+	SendSemanticTokens bool
+	NoObjects          bool
+	DisableWarnings    bool
+	// BestEffort continues parsing files with an incompatible CGE version.
+	BestEffort bool
+	// AssumeVersion sends the CGE version of the parser as the metadata if the file does not contain a valid version.
+	// Otherwise an empty version is sent in this case.
+	AssumeVersion bool
+	// ErrorTolerant sends all objects even if the file contains errors.
+	// Objects with syntax errors are marked as incomplete.
+	ErrorTolerant bool
+	// Lint configures the severities of the lint rules.
+	Lint LintConfig
+	// Limits restricts the resources used for parsing untrusted input.
+	Limits Limits
+	// PositionEncoding determines how columns are counted in the output. Offsets are always counted in bytes.
+	PositionEncoding PositionEncoding
+	// OneBased makes lines and columns in the output start at 1 instead of 0.
+	OneBased bool
+	// MaxErrors limits the number of reported errors (0: unlimited).
+	// All diagnostics are ordered by position and errors after the first MaxErrors are dropped.
+	MaxErrors int
 }
 
 type DiagnosticType int32
@@ -26,10 +47,94 @@ const (
 	DiagnosticError
 )
 
+type Diagnostic struct {
+	Type        DiagnosticType
+	Code        DiagnosticCode
+	Message     string
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+	// byte offsets (only set in the output of Parse)
+	StartOffset int
+	EndOffset   int
+	// Related contains other locations, which are part of the problem.
+	Related []RelatedLocation
+	// Fixes contains alternative changes to the source, which resolve the problem.
+	Fixes []Fix
+}
+
+// Fix is a machine-applicable change to the source described by Title.
+// The edits of a fix do not overlap and are sorted by position.
+type Fix struct {
+	Title string
+	Edits []Edit
+	// Safe fixes do not change the meaning of the file and can be applied without review.
+	Safe bool
+}
+
+func (f Fix) safe() Fix {
+	f.Safe = true
+	return f
+}
+
+func insertFix(title string, line, column int, text string) Fix {
+	return Fix{
+		Title: title,
+		Edits: []Edit{{StartLine: line, StartColumn: column, EndLine: line, EndColumn: column, Text: text}},
+	}
+}
+
+// insertAfterFix inserts text directly after the token.
+func insertAfterFix(title string, token Token, text string) Fix {
+	line, column := tokenEnd(token)
+	return insertFix(title, line, column, text)
+}
+
+func replaceFix(title string, token Token, text string) Fix {
+	return replaceSpanFix(title, token.Span(), text)
+}
+
+func replaceSpanFix(title string, span Span, text string) Fix {
+	return Fix{
+		Title: title,
+		Edits: []Edit{{StartLine: span.StartLine, StartColumn: span.StartColumn, EndLine: span.EndLine, EndColumn: span.EndColumn, Text: text}},
+	}
+}
+
+// removeFix deletes everything from the start of from to the start of until.
+func removeFix(title string, from, until Token) Fix {
+	return Fix{
+		Title: title,
+		Edits: []Edit{{StartLine: from.Line, StartColumn: from.Column, EndLine: until.Line, EndColumn: until.Column}},
+	}
+}
+
+type RelatedLocation struct {
+	Message     string
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+	StartOffset int
+	EndOffset   int
+}
+
+func relatedToken(token Token, message string) RelatedLocation {
+	return RelatedLocation{
+		Message:     message,
+		StartLine:   token.Line,
+		StartColumn: token.Column,
+		EndLine:     token.EndLine,
+		EndColumn:   token.EndColumn,
+	}
+}
+
 type Sender interface {
 	SendMetadata(version string) error
-	SendDiagnostic(diagnosticType DiagnosticType, message string, startLine, startColumn, endLine, endColumn int) error
-	SendToken(tokenType TokenType, lexeme string, line, column int) error
+	SendDiagnostic(diagnostic Diagnostic) error
+	SendToken(token Token) error
+	SendSemanticToken(token SemanticToken) error
 	SendObject(object Object) error
 }
 
@@ -40,6 +145,35 @@ type Object struct {
 	Type       TokenType
 	Name       Token
 	Properties []Property
+	// Span reaches from the keyword to the closing '}' or to the last parsed token of an incomplete declaration.
+	Span Span
+	// Incomplete is true if the declaration contains syntax errors.
+	// Properties only contains the successfully parsed properties.
+	Incomplete bool
+
+	// declared inline as the type of a property
+	inline bool
+	// preceded by a doc comment (set even if IncludeComments is disabled)
+	documented bool
+	// identifiers, which may be type references in the broken part of an incomplete declaration
+	possibleReferences []string
+}
+
+// Keyword returns the keyword of the declaration like 'type' or 'event'.
+func (o Object) Keyword() string {
+	switch o.Type {
+	case TTConfig:
+		return "config"
+	case TTCommand:
+		return "command"
+	case TTEvent:
+		return "event"
+	case TTType:
+		return "type"
+	case TTEnum:
+		return "enum"
+	}
+	return ""
 }
 
 func (o Object) String() string {
@@ -59,12 +193,20 @@ func (o Object) String() string {
 type Property struct {
 	Comment string
 	Name    string
-	Type    *PropertyType
+	// NameToken is the token of the name with its position.
+	NameToken Token
+	Type      *PropertyType
+	// Span reaches from the name to the end of the type without the doc comment.
+	Span Span
 }
 
 type PropertyType struct {
 	Token   Token
 	Generic *PropertyType
+	// Span covers the whole type including generics and inline declarations.
+	Span Span
+	// Declaration is the resolved declaration of a custom type (nil if undefined or not a custom type).
+	Declaration *Symbol
 }
 
 func (p Property) String() string {
@@ -80,203 +222,405 @@ type parser struct {
 	config Config
 
 	scanner *scanner
+	// first error returned by out
+	sendErr error
 
 	previous Token
 
+	gameName                Token
 	objects                 []Object
-	commands                map[string]struct{}
-	events                  map[string]struct{}
-	types                   map[string]struct{}
-	configObj               bool
+	symbols                 map[string]Symbol
 	accessedTypeIdentifiers []Token
+	// suppression comments read by advance
+	suppressions []suppressionComment
+
+	hadError   bool
+	errorCount int
+	// set when error recovery stopped outside of the current declaration.
+	// Syntax errors until the next declaration are not reported, because they are most likely caused by the first error.
+	cascading bool
+
+	// current number of nested property types
+	typeDepth     int
+	limitExceeded bool
 
-	hadError bool
+	// identifiers skipped during error recovery in the current top-level declaration
+	skipped []string
 }
 
+// Parse parses the CGE file read from input and sends the results to output.
+// The complete input is read into memory before scanning starts (at most Limits.MaxBytes plus one rune),
+// which also applies to OnlyMetadata.
 func Parse(input io.Reader, output Sender, config Config) error {
-	p := parser{
+	return ParseContext(context.Background(), input, output, config)
+}
+
+// ParseContext works like Parse but stops with a *CancelError when ctx is done.
+// The context is checked before every top-level declaration, so it does not interrupt reading the input.
+// I/O failures of input are returned as *ReadError and failures of output as *SendError.
+func ParseContext(ctx context.Context, input io.Reader, output Sender, config Config) error {
+	return newParser(newScanner(input, config.Limits), output, config).parse(ctx)
+}
+
+func newParser(scanner *scanner, output Sender, config Config) *parser {
+	return &parser{
 		out:                     output,
 		config:                  config,
-		scanner:                 newScanner(input),
+		scanner:                 scanner,
 		objects:                 make([]Object, 0, 32),
-		commands:                make(map[string]struct{}),
-		events:                  make(map[string]struct{}),
-		types:                   make(map[string]struct{}),
+		symbols:                 make(map[string]Symbol),
 		accessedTypeIdentifiers: make([]Token, 0),
 	}
-	return p.parse()
 }
 
-func (p *parser) parse() (err error) {
-	defer func() {
-		r := recover()
-		if e, ok := r.(error); ok {
-			err = e
-		} else if r != nil {
-			panic(r)
-		}
-	}()
+func (p *parser) parse(ctx context.Context) error {
+	p.out = &positionSender{Sender: p.out, converter: newPositionConverter(p.scanner.source, lineStarts(p.scanner.source), p.config)}
+	diagnostics := &diagnosticBuffer{Sender: p.out}
+	p.out = diagnostics
 
-	err = p.metadata()
+	err := p.metadata()
 	if err != nil {
-		if _, ok := err.(ParserError); ok {
-			err = nil
+		if _, ok := err.(ParserError); !ok {
+			return &SendError{Err: err}
 		}
-		return err
+		p.flushDiagnostics(diagnostics)
+		return p.failure()
 	}
 	if p.config.OnlyMetadata {
-		return nil
+		p.checkLimits()
+		p.flushDiagnostics(diagnostics)
+		return p.failure()
 	}
 
-	for p.peek(0).Type != TTEOF {
-		decl, err := p.declaration()
-		if err != nil {
-			if e, ok := err.(ParserError); ok {
-				p.skipBlock(e.inBlock)
-			}
-			continue
+	for p.scanner.peekToken(0).Type != TTEOF && !p.checkLimits() && p.failure() == nil {
+		if err := ctx.Err(); err != nil {
+			return &CancelError{Err: err}
 		}
-		p.objects = append(p.objects, decl)
+		p.topLevelDeclaration()
 	}
-
-	for _, id := range p.accessedTypeIdentifiers {
-		if _, ok := p.types[id.Lexeme]; !ok {
-			p.error(id, fmt.Sprintf("undefined type '%s'.", id.Lexeme), true)
-		}
+	if p.failure() != nil || p.checkLimits() {
+		// the declarations of a truncated file are neither checked nor sent
+		p.flushDiagnostics(diagnostics)
+		return p.failure()
 	}
 
-	if !p.configObj {
-		p.objects = append(p.objects, Object{
-			Type: TTConfig,
-		})
-	}
+	objects := p.objects
+	p.check()
+	p.flushDiagnostics(diagnostics)
 
-	p.detectDeclarationCycles()
+	if p.config.SendSemanticTokens {
+		for _, t := range classify(p.gameName, objects, p.accessedTypeIdentifiers) {
+			p.sent(p.out.SendSemanticToken(t))
+			if p.sendErr != nil {
+				break
+			}
+		}
+	}
 
-	if !p.config.NoObjects && !p.hadError {
+	if !p.config.NoObjects && (!p.hadError || p.config.ErrorTolerant) {
 		for _, o := range p.objects {
-			err := p.out.SendObject(o)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "failed to send object: %s", err)
+			p.sent(p.out.SendObject(o))
+			if p.sendErr != nil {
 				break
 			}
 		}
 	}
 
-	return nil
+	return p.failure()
 }
 
+// parserVersion is the CGE version of the parser. It is a variable, so that tests can use a release version.
+var parserVersion = cge.CGEVersion
+
+// metadata parses the file header.
+// Malformed fields are reported and skipped, so that the body can still be parsed.
+// A missing or malformed version is sent as an empty version unless AssumeVersion is enabled.
+// A ParserError is only returned if the file is incompatible with the parser and BestEffort is disabled.
 func (p *parser) metadata() error {
 	for p.match(TTComment) {
-		p.warn(p.previous, "game comments are deprecated")
+		p.warn(p.previous, CodeDeprecatedGameComment, "game comments are deprecated", removeFix("Remove game comment", p.previous, p.peek(0)).safe())
 	}
 
 	if p.match(TTGameName) {
-		p.warn(p.previous, "the 'name' metadata field is deprecated")
-		if !p.match(TTIdentifier) {
-			return p.error(p.peek(0), "expected identifier after 'name' keyword", false)
+		keyword := p.previous
+		if p.match(TTIdentifier) {
+			p.gameName = p.previous
+			p.warn(keyword, CodeDeprecatedNameField, "the 'name' metadata field is deprecated", removeFix("Remove 'name' field", keyword, p.peek(0)).safe())
+		} else {
+			p.warn(keyword, CodeDeprecatedNameField, "the 'name' metadata field is deprecated")
+			p.error(p.peek(0), CodeMalformedMetadata, "expected identifier after 'name' keyword", false)
+			p.skipMetadataField()
 		}
 	}
 
 	var version Token
+	hasVersion := false
 	for p.match(TTCGEVersion) {
-		switch p.previous.Type {
-		case TTCGEVersion:
-			if version.Lexeme != "" {
-				return p.error(p.previous, "duplicate 'cge' metadata field", false)
-			}
-			if p.previous.Lexeme == "version" {
-				p.warn(version, "the 'version' metadata field is deprecated; use 'cge' instead")
-			}
-			if !p.match(TTVersionNumber) {
-				return p.error(p.peek(0), "expected version number after 'cge' keyword", false)
-			}
-			version = p.previous
+		if hasVersion {
+			p.error(p.previous, CodeDuplicateMetadata, "duplicate 'cge' metadata field", false)
+			p.skipMetadataField()
+			continue
+		}
+		hasVersion = true
+		if p.previous.Lexeme == "version" {
+			p.warn(p.previous, CodeDeprecatedVersionField, "the 'version' metadata field is deprecated; use 'cge' instead", replaceFix("Replace 'version' with 'cge'", p.previous, "cge").safe())
+		}
+		if !p.match(TTVersionNumber) {
+			p.error(p.peek(0), CodeMalformedMetadata, "expected version number after 'cge' keyword", false)
+			p.skipMetadataField()
+			continue
 		}
+		version = p.previous
 	}
-	if version.Lexeme == "" {
-		return p.error(p.peek(0), "missing required 'cge' metadata field", false)
+	if !hasVersion {
+		p.error(p.peek(0), CodeMissingVersion, "missing required 'cge' metadata field", false)
 	}
 
-	err := p.out.SendMetadata(version.Lexeme)
+	versionNumber := version.Lexeme
+	if versionNumber == "" && p.config.AssumeVersion {
+		versionNumber = parserVersion
+	}
+	err := p.out.SendMetadata(versionNumber)
 	if err != nil {
 		return err
 	}
 
-	if !p.config.OnlyMetadata && !isVersionCompatible(version.Lexeme, cge.CGEVersion) {
-		return p.error(version, fmt.Sprintf("incompatible CGE version (file: %s, parser: %s)", version.Lexeme, cge.CGEVersion), false)
+	if version.Lexeme != "" && !p.config.OnlyMetadata && !isVersionCompatible(version.Lexeme, parserVersion) {
+		message := fmt.Sprintf("incompatible CGE version (file: %s, parser: %s)", version.Lexeme, parserVersion)
+		if !p.config.BestEffort {
+			return p.error(version, CodeIncompatibleVersion, message, false)
+		}
+		p.warn(version, CodeIncompatibleVersion, message)
 	}
 
 	return nil
 }
 
+// skipMetadataField skips all tokens until the start of the next metadata field or declaration.
+func (p *parser) skipMetadataField() {
+	for {
+		switch p.peek(0).Type {
+		case TTEOF, TTComment, TTGameName, TTCGEVersion, TTConfig, TTCommand, TTEvent, TTType, TTEnum:
+			return
+		}
+		p.advance()
+	}
+}
+
+// topLevelDeclaration parses a declaration and skips the rest of it in case of an error.
+// Declarations with errors are kept as incomplete objects if their type and name could be parsed.
+// It does not depend on any other declaration, which enables incremental reparsing.
+func (p *parser) topLevelDeclaration() {
+	p.cascading = false
+	if p.peek(0).Type == TTEOF {
+		// only suppression comments are left
+		p.skipSuppressions()
+		return
+	}
+	p.skipped = nil
+	accessed := len(p.accessedTypeIdentifiers)
+	decl, err := p.declaration()
+	if err != nil {
+		if e, ok := err.(ParserError); ok {
+			p.skipBlock(e.inBlock)
+		}
+		if !decl.Incomplete {
+			return
+		}
+	}
+	if decl.Incomplete {
+		// includes the types of properties dropped because of errors
+		for _, id := range p.accessedTypeIdentifiers[accessed:] {
+			decl.possibleReferences = append(decl.possibleReferences, id.Lexeme)
+		}
+		decl.possibleReferences = append(decl.possibleReferences, p.skipped...)
+	}
+	p.objects = append(p.objects, decl)
+}
+
+// check runs all checks which require knowledge of every declaration.
+func (p *parser) check() {
+	p.cascading = false
+	p.removeDuplicates()
+
+	undefined := make(map[string][]Token)
+	names := make([]string, 0)
+	for _, id := range p.accessedTypeIdentifiers {
+		if _, ok := p.symbols[id.Lexeme]; !ok {
+			if _, ok := undefined[id.Lexeme]; !ok {
+				names = append(names, id.Lexeme)
+			}
+			undefined[id.Lexeme] = append(undefined[id.Lexeme], id)
+		}
+	}
+	for _, name := range names {
+		p.undefinedType(undefined[name])
+	}
+
+	configObj := false
+	for _, o := range p.objects {
+		if o.Type == TTConfig {
+			configObj = true
+			break
+		}
+	}
+	if !configObj {
+		p.objects = append(p.objects, Object{
+			Type: TTConfig,
+		})
+	}
+
+	p.detectDeclarationCycles()
+	p.resolveReferences()
+	p.runLintRules()
+}
+
+// undefinedType reports all uses of an undefined type at the first use.
+func (p *parser) undefinedType(uses []Token) {
+	id := uses[0]
+	message := fmt.Sprintf("undefined type '%s'.", id.Lexeme)
+	if len(uses) > 1 {
+		message = fmt.Sprintf("undefined type '%s' (used %d times).", id.Lexeme, len(uses))
+	}
+	related := make([]RelatedLocation, 0, len(uses)-1)
+	for _, use := range uses[1:] {
+		related = append(related, relatedToken(use, "also used here"))
+	}
+
+	suggestion, ok := p.suggestType(id.Lexeme)
+	if !ok {
+		p.report(id, CodeUndefinedType, message, true, related, nil)
+		return
+	}
+	fix := Fix{Title: fmt.Sprintf("Replace with '%s'", suggestion)}
+	for _, use := range uses {
+		fix.Edits = append(fix.Edits, replaceFix("", use, suggestion).Edits...)
+	}
+	p.report(id, CodeUndefinedType, fmt.Sprintf("%s Did you mean '%s'?", message, suggestion), true, related, []Fix{fix})
+}
+
+// removeDuplicates reports and removes all objects whose name is already declared earlier in the file.
+func (p *parser) removeDuplicates() {
+	ordered := make([]*Object, len(p.objects))
+	for i := range p.objects {
+		ordered[i] = &p.objects[i]
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i].Name, ordered[j].Name
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+
+	var configObj *Token
+	commands := make(map[string]Token)
+	events := make(map[string]Token)
+	duplicates := make(map[*Object]struct{})
+	for _, o := range ordered {
+		name := o.Name
+		switch o.Type {
+		case TTConfig:
+			if configObj != nil {
+				p.error(name, CodeDuplicateDeclaration, "duplicate config object", false, relatedToken(*configObj, "first defined here"))
+				duplicates[o] = struct{}{}
+			} else {
+				configObj = &o.Name
+			}
+		case TTCommand:
+			if first, ok := commands[name.Lexeme]; ok {
+				p.error(name, CodeDuplicateDeclaration, fmt.Sprintf("command '%s' already defined", name.Lexeme), false, relatedToken(first, "first defined here"))
+				duplicates[o] = struct{}{}
+			} else {
+				commands[name.Lexeme] = name
+			}
+		case TTEvent:
+			if first, ok := events[name.Lexeme]; ok {
+				p.error(name, CodeDuplicateDeclaration, fmt.Sprintf("event '%s' already defined", name.Lexeme), false, relatedToken(first, "first defined here"))
+				duplicates[o] = struct{}{}
+			} else {
+				events[name.Lexeme] = name
+			}
+		case TTType, TTEnum:
+			if first, ok := p.symbols[name.Lexeme]; ok {
+				related := relatedToken(first.Name, "first defined here")
+				if o.inline {
+					p.error(name, CodeDuplicateDeclaration, fmt.Sprintf("type '%s' is already defined", name.Lexeme), true, related)
+				} else {
+					p.error(name, CodeDuplicateDeclaration, fmt.Sprintf("type '%s' already defined", name.Lexeme), false, related)
+				}
+				duplicates[o] = struct{}{}
+			} else {
+				p.symbols[name.Lexeme] = Symbol{
+					Kind: o.Type,
+					Name: name,
+					Span: o.Span,
+				}
+			}
+		}
+	}
+
+	if len(duplicates) == 0 {
+		return
+	}
+	objects := make([]Object, 0, len(p.objects)-len(duplicates))
+	for i := range p.objects {
+		if _, ok := duplicates[&p.objects[i]]; !ok {
+			objects = append(objects, p.objects[i])
+		}
+	}
+	p.objects = objects
+}
+
+// declaration parses a top-level declaration.
+// In case of an error after the name, the partially parsed object is returned marked as incomplete.
 func (p *parser) declaration() (Object, error) {
+	documented := p.peek(0).Type == TTComment
 	comment := p.comment()
+	errorCount := p.errorCount
 
 	if !p.match(TTConfig, TTCommand, TTEvent, TTType, TTEnum) {
 		if p.peek(0).Type == TTComment {
-			return Object{}, p.error(p.peek(0), "comment does not belong to an object", false)
+			return Object{}, p.error(p.peek(0), CodeMisplacedComment, "comment does not belong to an object", false)
+		}
+		if keyword, ok := suggest(p.peek(0).Lexeme, declarationKeywords); ok && p.peek(0).Type == TTIdentifier {
+			message := fmt.Sprintf("expected type declaration. Did you mean '%s'?", keyword)
+			return Object{}, p.errorWithFix(p.peek(0), CodeExpectedDeclaration, message, false, replaceFix(fmt.Sprintf("Replace with '%s'", keyword), p.peek(0), keyword))
 		}
-		return Object{}, p.error(p.peek(0), "expected type declaration", false)
+		return Object{}, p.error(p.peek(0), CodeExpectedDeclaration, "expected type declaration", false)
 	}
 
 	objectKeyword := p.previous
 
-	if objectKeyword.Type == TTConfig {
-		if p.configObj {
-			return Object{}, p.error(p.previous, "duplicate config object", false)
-		}
-		p.configObj = true
-	} else {
+	if objectKeyword.Type != TTConfig {
 		if !p.match(TTIdentifier) {
-			return Object{}, p.error(p.peek(0), fmt.Sprintf("expected identifier after '%s' keyword.", p.previous.Lexeme), false)
+			return Object{}, p.error(p.peek(0), CodeExpectedIdentifier, fmt.Sprintf("expected identifier after '%s' keyword.", p.previous.Lexeme), false)
 		}
 	}
-	name := p.previous
-
-	switch objectKeyword.Type {
-	case TTCommand:
-		if _, ok := p.commands[name.Lexeme]; ok {
-			return Object{}, p.error(name, fmt.Sprintf("command '%s' already defined", name.Lexeme), false)
-		}
-		p.commands[name.Lexeme] = struct{}{}
-	case TTEvent:
-		if _, ok := p.events[name.Lexeme]; ok {
-			return Object{}, p.error(name, fmt.Sprintf("event '%s' already defined", name.Lexeme), false)
-		}
-		p.events[name.Lexeme] = struct{}{}
-	case TTType, TTEnum:
-		if _, ok := p.types[name.Lexeme]; ok {
-			return Object{}, p.error(name, fmt.Sprintf("type '%s' already defined", name.Lexeme), false)
-		}
-		p.types[name.Lexeme] = struct{}{}
+	object := Object{
+		Comment:    comment,
+		Type:       objectKeyword.Type,
+		Name:       p.previous,
+		Properties: make([]Property, 0),
+		Span:       p.spanFrom(objectKeyword),
+		Incomplete: true,
+		documented: documented,
 	}
 
 	if !p.match(TTOpenCurly) {
 		if objectKeyword.Type == TTConfig {
-			return Object{}, p.error(p.peek(0), fmt.Sprintf("expected block after '%s' keyword", objectKeyword.Lexeme), true)
+			return object, p.error(p.peek(0), CodeExpectedBlock, fmt.Sprintf("expected block after '%s' keyword", objectKeyword.Lexeme), true)
 		} else {
-			return Object{}, p.error(p.peek(0), fmt.Sprintf("expected block after %s name", objectKeyword.Lexeme), true)
+			return object, p.error(p.peek(0), CodeExpectedBlock, fmt.Sprintf("expected block after %s name", objectKeyword.Lexeme), true)
 		}
 	}
 
-	var properties []Property
 	var err error
 	if objectKeyword.Type == TTEnum {
-		properties, err = p.enumBlock()
+		object.Properties, err = p.enumBlock()
 	} else {
-		properties, err = p.block()
+		object.Properties, err = p.block()
 	}
-	if err != nil {
-		return Object{}, err
-	}
-
-	return Object{
-		Comment:    comment,
-		Type:       objectKeyword.Type,
-		Name:       name,
-		Properties: properties,
-	}, nil
+	object.Span = p.spanFrom(objectKeyword)
+	object.Incomplete = p.errorCount > errorCount
+	return object, err
 }
 
 func (p *parser) block() ([]Property, error) {
@@ -286,16 +630,23 @@ func (p *parser) block() ([]Property, error) {
 		property, err := p.property()
 		if err != nil {
 			p.skipProperty()
+			if p.cascading {
+				break
+			}
 			continue
 		}
 		properties = append(properties, property)
 		if !p.match(TTComma) {
+			if p.missingSeparator(true) {
+				continue
+			}
 			break
 		}
 	}
 
+	properties = p.removeDuplicateProperties(properties, "property")
 	if !p.match(TTCloseCurly) {
-		return nil, p.error(p.peek(0), "expected '}' after block", true)
+		return properties, p.errorWithFix(p.peek(0), CodeUnclosedBlock, "expected '}' after block", true, insertAfterFix("Insert '}'", p.previous, "}"))
 	}
 
 	return properties, nil
@@ -308,31 +659,66 @@ func (p *parser) enumBlock() ([]Property, error) {
 		property, err := p.enumValue()
 		if err != nil {
 			p.skipProperty()
+			if p.cascading {
+				break
+			}
 			continue
 		}
 		properties = append(properties, property)
 		if !p.match(TTComma) {
+			if p.missingSeparator(false) {
+				continue
+			}
 			break
 		}
 	}
 
+	properties = p.removeDuplicateProperties(properties, "enum member")
 	if !p.match(TTCloseCurly) {
-		return nil, p.error(p.peek(0), "expected '}' after block", true)
+		return properties, p.errorWithFix(p.peek(0), CodeUnclosedBlock, "expected '}' after block", true, insertAfterFix("Insert '}'", p.previous, "}"))
 	}
 
 	return properties, nil
 }
 
+// removeDuplicateProperties reports and removes properties whose name is already used in the same block.
+// Names which are only equal after the conversion to PascalCase are reported as well, but not removed.
+func (p *parser) removeDuplicateProperties(properties []Property, kind string) []Property {
+	names := make(map[string]Token, len(properties))
+	pascalNames := make(map[string]Token, len(properties))
+	result := properties[:0]
+	for _, prop := range properties {
+		name := prop.NameToken
+		if first, ok := names[name.Lexeme]; ok {
+			p.semanticError(name, CodeDuplicateProperty, fmt.Sprintf("%s '%s' is already defined", kind, name.Lexeme), relatedToken(first, "first defined here"))
+			continue
+		}
+		names[name.Lexeme] = name
+
+		pascal := strings.ToLower(strings.ReplaceAll(name.Lexeme, "_", ""))
+		if first, ok := pascalNames[pascal]; ok {
+			p.semanticError(name, CodePropertyNameClash, fmt.Sprintf("%s '%s' clashes with '%s' after conversion to PascalCase", kind, name.Lexeme, first.Lexeme), relatedToken(first, fmt.Sprintf("'%s' defined here", first.Lexeme)))
+		} else {
+			pascalNames[pascal] = name
+		}
+		result = append(result, prop)
+	}
+	return result
+}
+
 func (p *parser) property() (Property, error) {
 	comment := p.comment()
 
 	if !p.match(TTIdentifier) {
-		return Property{}, p.error(p.peek(0), "expected property name", true)
+		return Property{}, p.error(p.peek(0), CodeExpectedIdentifier, "expected property name", true)
 	}
 	name := p.previous
 
 	if !p.match(TTColon) {
-		return Property{}, p.error(p.peek(0), "expected ':' after property name", true)
+		err := p.errorWithFix(p.peek(0), CodeExpectedColon, "expected ':' after property name", true, insertAfterFix("Insert ':'", name, ":").safe())
+		if !isTypeStart(p.peek(0).Type) || p.peek(1).Type == TTColon {
+			return Property{}, err
+		}
 	}
 
 	propertyType, err := p.propertyType()
@@ -341,9 +727,11 @@ func (p *parser) property() (Property, error) {
 	}
 
 	return Property{
-		Comment: comment,
-		Name:    name.Lexeme,
-		Type:    propertyType,
+		Comment:   comment,
+		Name:      name.Lexeme,
+		NameToken: name,
+		Type:      propertyType,
+		Span:      p.spanFrom(name),
 	}, nil
 }
 
@@ -351,63 +739,81 @@ func (p *parser) enumValue() (Property, error) {
 	comment := p.comment()
 
 	if !p.match(TTIdentifier) {
-		return Property{}, p.error(p.peek(0), "expected property name", true)
+		return Property{}, p.error(p.peek(0), CodeExpectedIdentifier, "expected property name", true)
 	}
 	name := p.previous
 
 	return Property{
-		Comment: comment,
-		Name:    name.Lexeme,
+		Comment:   comment,
+		Name:      name.Lexeme,
+		NameToken: name,
+		Span:      name.Span(),
 	}, nil
 }
 
 func (p *parser) propertyType() (*PropertyType, error) {
-	if !p.match(TTString, TTBool, TTInt32, TTInt64, TTFloat32, TTFloat64, TTMap, TTList, TTIdentifier, TTType, TTEnum) {
-		return &PropertyType{}, p.error(p.peek(0), "expected type after property name", true)
+	if !isTypeStart(p.peek(0).Type) {
+		return &PropertyType{}, p.error(p.peek(0), CodeExpectedType, "expected type after property name", true)
 	}
 
-	propertyType := p.previous
+	p.typeDepth++
+	defer func() {
+		p.typeDepth--
+	}()
+	// the outermost type is not nested
+	if max := p.config.Limits.maxNestingDepth(); p.typeDepth-1 > max {
+		return &PropertyType{}, p.error(p.peek(0), CodeLimitExceeded, fmt.Sprintf("type is nested too deeply (maximum depth: %d)", max), true)
+	}
+
+	propertyType := p.advance()
+	start := propertyType
 	var generic *PropertyType
 
+	if name, ok := typeAliases[propertyType.Lexeme]; ok {
+		p.lint(propertyType.Span(), CodeTypeAlias, fmt.Sprintf("'%s' is an alias of '%s'", propertyType.Lexeme, name), nil, replaceFix(fmt.Sprintf("Replace with '%s'", name), propertyType, name).safe())
+	}
+
 	switch propertyType.Type {
 	case TTIdentifier:
 		p.accessedTypeIdentifiers = append(p.accessedTypeIdentifiers, propertyType)
 	case TTType, TTEnum:
 		if !p.match(TTIdentifier) {
-			return &PropertyType{}, p.error(p.peek(0), "expected identifier after 'type' keyword", true)
+			return &PropertyType{}, p.error(p.peek(0), CodeExpectedIdentifier, "expected identifier after 'type' keyword", true)
 		}
 
-		identifier := p.previous
-		if _, ok := p.types[identifier.Lexeme]; ok {
-			return &PropertyType{}, p.error(identifier, fmt.Sprintf("type '%s' is already defined", identifier.Lexeme), true)
+		object := Object{
+			Type:       propertyType.Type,
+			Name:       p.previous,
+			Properties: make([]Property, 0),
+			Span:       p.spanFrom(propertyType),
+			Incomplete: true,
+			inline:     true,
 		}
-		p.types[identifier.Lexeme] = struct{}{}
+		identifier := p.previous
 
 		if !p.match(TTOpenCurly) {
-			return &PropertyType{}, p.error(p.peek(0), "expected block after type name", true)
+			p.objects = append(p.objects, object)
+			return &PropertyType{}, p.error(p.peek(0), CodeExpectedBlock, "expected block after type name", true)
 		}
 
-		var properties []Property
+		errorCount := p.errorCount
 		var err error
 		if propertyType.Type == TTType {
-			properties, err = p.block()
+			object.Properties, err = p.block()
 		} else {
-			properties, err = p.enumBlock()
+			object.Properties, err = p.enumBlock()
 		}
+		object.Span = p.spanFrom(propertyType)
+		object.Incomplete = p.errorCount > errorCount
+		p.objects = append(p.objects, object)
 		if err != nil {
 			return &PropertyType{}, err
 		}
 
-		p.objects = append(p.objects, Object{
-			Type:       propertyType.Type,
-			Name:       identifier,
-			Properties: properties,
-		})
-
 		propertyType = identifier
 	case TTMap, TTList:
 		if !p.match(TTLess) {
-			return &PropertyType{}, p.error(p.peek(0), "expected generic", true)
+			return &PropertyType{}, p.error(p.peek(0), CodeMalformedGeneric, "expected generic", true)
 		}
 
 		var err error
@@ -417,16 +823,60 @@ func (p *parser) propertyType() (*PropertyType, error) {
 		}
 
 		if !p.match(TTGreater) {
-			return &PropertyType{}, p.error(p.peek(0), "expected '>' after generic value", true)
+			return &PropertyType{}, p.errorWithFix(p.peek(0), CodeMalformedGeneric, "expected '>' after generic value", true, insertAfterFix("Insert '>'", p.previous, ">"))
 		}
 	}
 
 	return &PropertyType{
 		Token:   propertyType,
 		Generic: generic,
+		Span:    p.spanFrom(start),
 	}, nil
 }
 
+// spanFrom returns the span from the start of the token to the end of the previous token.
+func (p *parser) spanFrom(start Token) Span {
+	return start.Span().to(p.previous.Span())
+}
+
+// typeAliases maps alternative names of primitive types to their canonical name.
+var typeAliases = map[string]string{
+	"int":   "int32",
+	"float": "float64",
+}
+
+func isTypeStart(tokenType TokenType) bool {
+	switch tokenType {
+	case TTString, TTBool, TTInt32, TTInt64, TTFloat32, TTFloat64, TTMap, TTList, TTIdentifier, TTType, TTEnum:
+		return true
+	}
+	return false
+}
+
+// missingSeparator reports a missing comma if the next token starts another property.
+// The caller can continue parsing the block if it returns true.
+func (p *parser) missingSeparator(typed bool) bool {
+	if p.peekAfterComments(0).Type != TTIdentifier || (typed && p.peekAfterComments(1).Type != TTColon) {
+		return false
+	}
+	p.errorWithFix(p.peek(0), CodeExpectedComma, "expected ',' between properties", true, insertAfterFix("Insert ','", p.previous, ",").safe())
+	return true
+}
+
+// peekAfterComments works like peek but ignores comments.
+func (p *parser) peekAfterComments(offset int) Token {
+	for i := 0; ; i++ {
+		token := p.peek(i)
+		if token.Type == TTComment {
+			continue
+		}
+		if offset == 0 {
+			return token
+		}
+		offset--
+	}
+}
+
 func (p *parser) comment() string {
 	var comments []string
 	for p.match(TTComment) {
@@ -438,17 +888,39 @@ func (p *parser) comment() string {
 }
 
 func (p *parser) advance() Token {
+	p.skipSuppressions()
+	token := p.nextToken()
+	p.previous = token
+	return token
+}
+
+func (p *parser) nextToken() Token {
 	token := p.scanner.nextToken()
 
 	if p.config.SendTokens && token.Type != TTError {
-		err := p.out.SendToken(token.Type, token.Lexeme, token.Line, token.Column)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to send token: %s", err)
+		p.sent(p.out.SendToken(token))
+	}
+	return token
+}
+
+// skipSuppressions reads all suppression comments and reports all recoverable scanner errors in front of the next token.
+// They are invisible to the grammar, so that they can be placed anywhere.
+func (p *parser) skipSuppressions() {
+	for {
+		token := p.scanner.peekToken(0)
+		switch {
+		case isSuppressionComment(token):
+			p.suppressions = append(p.suppressions, newSuppressionComment(p.nextToken(), p.previous))
+		case isRecoverableError(token):
+			p.semanticError(p.nextToken(), token.errorCode, token.Lexeme)
+		default:
+			return
 		}
 	}
+}
 
-	p.previous = token
-	return token
+func isRecoverableError(token Token) bool {
+	return token.Type == TTError && token.recoverable
 }
 
 func (p *parser) match(types ...TokenType) bool {
@@ -461,8 +933,18 @@ func (p *parser) match(types ...TokenType) bool {
 	return false
 }
 
+// peek returns the token at offset ignoring suppression comments and recoverable errors.
 func (p *parser) peek(offset int) Token {
-	return p.scanner.peekToken(offset)
+	for i := 0; ; i++ {
+		token := p.scanner.peekToken(i)
+		if isSuppressionComment(token) || isRecoverableError(token) {
+			continue
+		}
+		if offset == 0 {
+			return token
+		}
+		offset--
+	}
 }
 
 func (p *parser) skipBlock(inBlock bool) {
@@ -472,34 +954,36 @@ func (p *parser) skipBlock(inBlock bool) {
 
 	if !inBlock {
 		for {
-			if p.match(TTEOF) {
+			if p.match(TTEOF) || isRecoveryPoint(p.peek(0)) {
 				return
 			}
 			if p.match(TTOpenCurly) {
 				break
 			}
-			p.advance()
+			p.skip()
 		}
 	}
 
 	nestingLevel := 1
-	for p.peek(0).Type != TTEOF && nestingLevel > 0 {
+	for p.peek(0).Type != TTEOF && nestingLevel > 0 && !isRecoveryPoint(p.peek(0)) {
 		if p.peek(0).Type == TTOpenCurly {
 			nestingLevel++
 		} else if p.peek(0).Type == TTCloseCurly {
 			nestingLevel--
 		}
-		p.advance()
+		p.skip()
 	}
 }
 
+// skipProperty skips to the start of the next property in the block.
+// If it reaches the start of the next declaration or EOF instead, all following errors of the declaration are cascading.
 func (p *parser) skipProperty() {
-	if p.peek(0).Type == TTEOF {
-		return
-	}
-
 	nestingLevel := 0
-	for p.peek(0).Type != TTEOF {
+	for {
+		if p.peek(0).Type == TTEOF || isRecoveryPoint(p.peek(0)) {
+			p.cascading = true
+			return
+		}
 		if p.peek(0).Type == TTOpenCurly {
 			nestingLevel++
 		} else if p.peek(0).Type == TTCloseCurly {
@@ -511,18 +995,43 @@ func (p *parser) skipProperty() {
 		if nestingLevel == 0 && p.match(TTComma) {
 			return
 		}
-		p.advance()
+		p.skip()
 	}
 }
 
-func (p *parser) warn(token Token, message string) {
+// skip advances during error recovery and remembers skipped identifiers, which might be type references.
+func (p *parser) skip() {
+	if p.advance().Type == TTIdentifier {
+		p.skipped = append(p.skipped, p.previous.Lexeme)
+	}
+}
+
+// isRecoveryPoint returns true if the token most likely starts a new declaration.
+// 'type' and 'enum' also start inline declarations, so they are only recovery points at the start of a line.
+func isRecoveryPoint(token Token) bool {
+	switch token.Type {
+	case TTConfig, TTCommand, TTEvent:
+		return true
+	case TTType, TTEnum:
+		return token.Column == 0
+	}
+	return false
+}
+
+func (p *parser) warn(token Token, code DiagnosticCode, message string, fixes ...Fix) {
 	if p.config.DisableWarnings {
 		return
 	}
-	err := p.out.SendDiagnostic(DiagnosticWarning, message, token.Line, token.Column, token.Line, token.Column+len(token.Lexeme))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to send warning '[%d:%d] %s': %s", token.Line, token.Column, message, err)
-	}
+	p.sent(p.out.SendDiagnostic(Diagnostic{
+		Type:        DiagnosticWarning,
+		Code:        code,
+		Message:     message,
+		StartLine:   token.Line,
+		StartColumn: token.Column,
+		EndLine:     token.EndLine,
+		EndColumn:   token.EndColumn,
+		Fixes:       fixes,
+	}))
 }
 
 type ParserError struct {
@@ -535,21 +1044,66 @@ func (p ParserError) Error() string {
 	return p.Message
 }
 
-func (p *parser) error(token Token, message string, inBlock bool) error {
+func (p *parser) error(token Token, code DiagnosticCode, message string, inBlock bool, related ...RelatedLocation) error {
+	if p.cascading {
+		return p.cascadingError(token, message, inBlock)
+	}
+	return p.report(token, code, message, inBlock, related, nil)
+}
+
+// semanticError reports an error, which does not mark the surrounding declaration as incomplete.
+// It is independent of other errors and therefore never cascading.
+func (p *parser) semanticError(token Token, code DiagnosticCode, message string, related ...RelatedLocation) {
+	errorCount := p.errorCount
+	p.report(token, code, message, true, related, nil)
+	p.errorCount = errorCount
+}
+
+func (p *parser) errorWithFix(token Token, code DiagnosticCode, message string, inBlock bool, fix Fix) error {
+	if p.cascading {
+		return p.cascadingError(token, message, inBlock)
+	}
+	return p.report(token, code, message, inBlock, nil, []Fix{fix})
+}
+
+// cascadingError counts the error like report without sending it.
+func (p *parser) cascadingError(token Token, message string, inBlock bool) error {
 	p.hadError = true
+	p.errorCount++
+	return ParserError{Token: token, Message: message, inBlock: inBlock}
+}
+
+func (p *parser) report(token Token, code DiagnosticCode, message string, inBlock bool, related []RelatedLocation, fixes []Fix) error {
+	p.hadError = true
+	p.errorCount++
+	if token.Type == TTEOF && p.scanner != nil && (p.scanner.limitError != nil || p.scanner.err != nil) {
+		// the input was truncated, the limit or read error is reported instead
+		return ParserError{Token: token, Message: message, inBlock: inBlock}
+	}
 	if token.Type == TTError {
+		code = token.errorCode
 		message = token.Lexeme
-		token.Lexeme = " "
+		fixes = nil
+		if token.fix != nil {
+			fixes = []Fix{*token.fix}
+		}
 	}
 	perr := ParserError{
 		Token:   token,
 		Message: message,
 		inBlock: inBlock,
 	}
-	err := p.out.SendDiagnostic(DiagnosticError, message, token.Line, token.Column, token.Line, token.Column+len(token.Lexeme))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to send error '[%d:%d] %s': %s", token.Line, token.Column, message, err)
-	}
+	p.sent(p.out.SendDiagnostic(Diagnostic{
+		Type:        DiagnosticError,
+		Code:        code,
+		Message:     message,
+		StartLine:   token.Line,
+		StartColumn: token.Column,
+		EndLine:     token.EndLine,
+		EndColumn:   token.EndColumn,
+		Related:     related,
+		Fixes:       fixes,
+	}))
 	return perr
 }
 
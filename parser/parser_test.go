@@ -0,0 +1,305 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDuplicateProperties(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"property", "cge 0.5\nevent hit { damage: int32, damage: float64 }\n", []string{"1:27 CGE0031"}},
+		{"enum member", "cge 0.5\nenum color { red, red }\nevent e { c: color }\n", []string{"1:18 CGE0031"}},
+		{"pascal case clash", "cge 0.5\nevent e { player_name: string, playername: string, p_layer_name: string }\n", []string{"1:31 CGE0032", "1:51 CGE0032"}},
+		{"inline type", "cge 0.5\nevent e { x: type a { y: int32, y: int32 } }\n", []string{"1:32 CGE0031"}},
+		{"different blocks", "cge 0.5\nevent a { x: string }\nevent b { x: string }\n", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			err := Parse(strings.NewReader(tt.source), rec, Config{DisableWarnings: true})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := diagnosticSummary(rec.diagnostics); !equalStrings(got, tt.want) {
+				t.Errorf("Parse() diagnostics = %v, want %v", got, tt.want)
+			}
+			for _, d := range rec.diagnostics {
+				if len(d.Related) != 1 || d.Related[0].StartLine != 1 || d.Related[0].StartColumn >= d.StartColumn {
+					t.Errorf("diagnostic %q does not point to the first definition: %+v", d.Message, d.Related)
+				}
+			}
+		})
+	}
+}
+
+type objectRecorder struct {
+	discardSender
+	objects []Object
+}
+
+func (r *objectRecorder) SendObject(object Object) error {
+	r.objects = append(r.objects, object)
+	return nil
+}
+
+func TestSpans(t *testing.T) {
+	source := "cge 0.5\nevent hit {\n\tdamage: map<list<int32>>,\n\tpos: type vec { x: float64 },\n}\n"
+	rec := &objectRecorder{}
+	if err := Parse(strings.NewReader(source), rec, Config{DisableWarnings: true}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	spans := make(map[string]Span)
+	for _, o := range rec.objects {
+		spans[o.Name.Lexeme] = o.Span
+		for _, p := range o.Properties {
+			spans[o.Name.Lexeme+"."+p.Name] = p.Span
+			spans[o.Name.Lexeme+"."+p.Name+":"] = p.Type.Span
+		}
+	}
+	want := map[string]Span{
+		"hit":         {1, 0, 4, 1},
+		"hit.damage":  {2, 1, 2, 25},
+		"hit.damage:": {2, 9, 2, 25},
+		"hit.pos:":    {3, 6, 3, 29},
+		"vec":         {3, 6, 3, 29},
+		"vec.x":       {3, 17, 3, 27},
+	}
+	for name, span := range want {
+		if spans[name] != span {
+			t.Errorf("span of %s = %+v, want %+v", name, spans[name], span)
+		}
+	}
+}
+
+func TestCascadingErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"missing closing brace", "cge 0.5\nevent a {\n\tx: string\nevent b { y: c }\n", []string{"3:0 CGE0011", "3:13 CGE0012"}},
+		{"missing generic end at next declaration", "cge 0.5\nevent a {\n\tx: list<string\ncommand b { y: c }\n", []string{"3:0 CGE0015", "3:15 CGE0012"}},
+		{"missing generic end at eof", "cge 0.5\nevent a { x: list<string", []string{"1:24 CGE0015"}},
+		{"independent properties", "cge 0.5\nevent a { x: , y: }\n", []string{"1:13 CGE0014", "1:18 CGE0014"}},
+		{"inline type at start of line", "cge 0.5\nevent a {\n\tx: list<string\ntype b { y: string }\n", []string{"3:0 CGE0015"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			err := Parse(strings.NewReader(tt.source), rec, Config{DisableWarnings: true})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := diagnosticSummary(rec.diagnostics); !equalStrings(got, tt.want) {
+				t.Errorf("Parse() diagnostics = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndefinedTypeUses(t *testing.T) {
+	rec := &recorder{}
+	source := "cge 0.5\ntype player {}\nevent a { x: playr, y: list<playr> }\ncommand b { z: playr }\n"
+	if err := Parse(strings.NewReader(source), rec, Config{DisableWarnings: true}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(rec.diagnostics) != 1 {
+		t.Fatalf("diagnostics = %v, want one", diagnosticSummary(rec.diagnostics))
+	}
+	d := rec.diagnostics[0]
+	if d.Message != "undefined type 'playr' (used 3 times). Did you mean 'player'?" || d.StartLine != 2 || d.StartColumn != 13 {
+		t.Errorf("diagnostic = %d:%d %q", d.StartLine, d.StartColumn, d.Message)
+	}
+	if len(d.Related) != 2 || len(d.Fixes) != 1 || len(d.Fixes[0].Edits) != 3 {
+		t.Errorf("related = %+v, fixes = %+v, want 2 related locations and 1 fix with 3 edits", d.Related, d.Fixes)
+	}
+}
+
+func TestDiagnosticOrder(t *testing.T) {
+	source := "cge 0.5\ntype b { x: a }\ntype a { y: b }\nevent e { z: int, w: undefined }\nevent e {}\n"
+	for _, maxErrors := range []int{0, 2} {
+		rec := &recorder{}
+		if err := Parse(strings.NewReader(source), rec, Config{MaxErrors: maxErrors}); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		got := make([]string, 0, len(rec.diagnostics))
+		for _, d := range rec.diagnostics {
+			got = append(got, fmt.Sprintf("%d:%d %s", d.StartLine, d.StartColumn, d.Code))
+		}
+		want := []string{"1:5 CGE0017", "3:13 CGE0022", "3:21 CGE0012", "4:6 CGE0016"}
+		if maxErrors == 2 {
+			want = []string{"1:5 CGE0017", "3:13 CGE0022", "3:21 CGE0012"}
+		}
+		if !equalStrings(got, want) {
+			t.Errorf("MaxErrors %d: diagnostics = %v, want %v", maxErrors, got, want)
+		}
+	}
+}
+
+func TestErrorTolerant(t *testing.T) {
+	source := "cge 0.5\nevent a { x: int32, y: }\nevent b { z: string }\ntype c { w: undefined }\nevent d { v: list<int32 }\ncommand\n"
+	tests := []struct {
+		errorTolerant bool
+		want          []string
+	}{
+		{false, nil},
+		// semantic errors like undefined types do not make a declaration incomplete
+		{true, []string{"a incomplete [x]", "b [z]", "c [w]", "d incomplete []", " []"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("error-tolerant=%t", tt.errorTolerant), func(t *testing.T) {
+			rec := &objectRecorder{}
+			err := Parse(strings.NewReader(source), rec, Config{ErrorTolerant: tt.errorTolerant})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var got []string
+			for _, o := range rec.objects {
+				names := make([]string, 0, len(o.Properties))
+				for _, p := range o.Properties {
+					names = append(names, p.Name)
+				}
+				summary := o.Name.Lexeme
+				if o.Incomplete {
+					summary += " incomplete"
+				}
+				got = append(got, fmt.Sprintf("%s %v", summary, names))
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("Parse() objects = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelatedLocations(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"command", "cge 0.5\ncommand a {}\ncommand a {}\n", []string{`2:8 CGE0016 -> 1:8-1:9 "first defined here"`}},
+		{"event defined three times", "cge 0.5\nevent e {}\nevent e {}\nevent e {}\n", []string{
+			`2:6 CGE0016 -> 1:6-1:7 "first defined here"`,
+			`3:6 CGE0016 -> 1:6-1:7 "first defined here"`,
+		}},
+		{"enum and type", "cge 0.5\ntype t {}\nenum t { x }\nevent e { y: t }\n", []string{`2:5 CGE0016 -> 1:5-1:6 "first defined here"`}},
+		{"config", "cge 0.5\nconfig {}\nconfig {}\n", []string{`2:0 CGE0016 -> 1:0-1:6 "first defined here"`}},
+		{"inline type", "cge 0.5\nevent e { y: type t {}, z: t }\ntype t {}\n", []string{`2:5 CGE0016 -> 1:18-1:19 "first defined here"`}},
+		{"property defined three times", "cge 0.5\nevent e { x: int32, y: int32, x: string, x: bool }\n", []string{
+			`1:30 CGE0031 -> 1:10-1:11 "first defined here"`,
+			`1:41 CGE0031 -> 1:10-1:11 "first defined here"`,
+		}},
+		{"enum member", "cge 0.5\nenum c { red, blue, red }\nevent e { c: c }\n", []string{`1:20 CGE0031 -> 1:9-1:12 "first defined here"`}},
+		{"property name clash", "cge 0.5\nevent e { player_name: string, playername: string }\n", []string{`1:31 CGE0032 -> 1:10-1:21 "'player_name' defined here"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			err := Parse(strings.NewReader(tt.source), rec, Config{DisableWarnings: true})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got := make([]string, 0, len(rec.diagnostics))
+			for _, d := range rec.diagnostics {
+				summary := fmt.Sprintf("%d:%d %s", d.StartLine, d.StartColumn, d.Code)
+				for _, r := range d.Related {
+					summary += fmt.Sprintf(" -> %d:%d-%d:%d %q", r.StartLine, r.StartColumn, r.EndLine, r.EndColumn, r.Message)
+				}
+				got = append(got, summary)
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("Parse() diagnostics = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataRecovery(t *testing.T) {
+	tests := []struct {
+		name               string
+		source             string
+		want               []string
+		wantVersion        string
+		wantAssumedVersion string
+	}{
+		{"missing version", "event a { x: undefined }\n", []string{"0:0 CGE0003", "0:13 CGE0012"}, "", "0.5"},
+		{"malformed version", "cge\nevent a { x: undefined }\n", []string{"1:0 CGE0004", "1:13 CGE0012"}, "", "0.5"},
+		{"malformed name and duplicate version", "name\ncge 0.5 cge 0.5\nevent a { x: undefined }\n", []string{"0:0 CGE0019", "1:0 CGE0004", "1:8 CGE0005", "2:13 CGE0012"}, "0.5", "0.5"},
+	}
+	setParserVersion(t, "0.5")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, assumeVersion := range []bool{false, true} {
+				rec := &recorder{}
+				err := Parse(strings.NewReader(tt.source), rec, Config{AssumeVersion: assumeVersion})
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				// the errors in the body are reported as well
+				if got := diagnosticSummary(rec.diagnostics); !equalStrings(got, tt.want) {
+					t.Errorf("AssumeVersion %t: diagnostics = %v, want %v", assumeVersion, got, tt.want)
+				}
+				wantVersion := tt.wantVersion
+				if assumeVersion {
+					wantVersion = tt.wantAssumedVersion
+				}
+				if rec.cgeVersion != wantVersion {
+					t.Errorf("AssumeVersion %t: version = %q, want %q", assumeVersion, rec.cgeVersion, wantVersion)
+				}
+			}
+		})
+	}
+}
+
+func TestBestEffort(t *testing.T) {
+	setParserVersion(t, "0.5")
+	source := "cge 0.4\nevent a { x: undefined }\n"
+	tests := []struct {
+		bestEffort   bool
+		want         []string
+		wantSeverity DiagnosticType
+	}{
+		// the body is not parsed
+		{false, []string{"0:4 CGE0006"}, DiagnosticError},
+		{true, []string{"0:4 CGE0006", "1:13 CGE0012"}, DiagnosticWarning},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("best-effort=%t", tt.bestEffort), func(t *testing.T) {
+			rec := &recorder{}
+			err := Parse(strings.NewReader(source), rec, Config{BestEffort: tt.bestEffort})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := diagnosticSummary(rec.diagnostics); !equalStrings(got, tt.want) {
+				t.Fatalf("diagnostics = %v, want %v", got, tt.want)
+			}
+			if rec.diagnostics[0].Type != tt.wantSeverity {
+				t.Errorf("severity of %q = %d, want %d", rec.diagnostics[0].Message, rec.diagnostics[0].Type, tt.wantSeverity)
+			}
+			if rec.cgeVersion != "0.4" {
+				t.Errorf("version = %q, want 0.4", rec.cgeVersion)
+			}
+		})
+	}
+}
+
+// setParserVersion sets the version of the parser for the duration of the test.
+func setParserVersion(t *testing.T, version string) {
+	t.Helper()
+	previous := parserVersion
+	parserVersion = version
+	t.Cleanup(func() {
+		parserVersion = previous
+	})
+}
@@ -0,0 +1,241 @@
+package parser
+
+import (
+	"unicode/utf8"
+)
+
+// PositionEncoding determines how columns are counted in the output of Parse.
+// Internally, columns always count Unicode code points.
+type PositionEncoding int
+
+const (
+	// PositionUTF32 counts Unicode code points.
+	PositionUTF32 PositionEncoding = iota
+	// PositionUTF16 counts UTF-16 code units like LSP clients by default.
+	PositionUTF16
+	// PositionUTF8 counts bytes.
+	PositionUTF8
+)
+
+// String returns the name of the encoding as used by LSP, e.g. 'utf-16'.
+func (e PositionEncoding) String() string {
+	switch e {
+	case PositionUTF32:
+		return "utf-32"
+	case PositionUTF16:
+		return "utf-16"
+	case PositionUTF8:
+		return "utf-8"
+	}
+	return "unknown"
+}
+
+func ParsePositionEncoding(encoding string) (PositionEncoding, bool) {
+	for e := PositionUTF32; e <= PositionUTF8; e++ {
+		if e.String() == encoding {
+			return e, true
+		}
+	}
+	return PositionUTF32, false
+}
+
+// positionConverter converts positions of the parser (code points, '\r' ignored) to the configured encoding
+// and computes byte offsets.
+type positionConverter struct {
+	source     string
+	lineStarts []int
+	encoding   PositionEncoding
+	// 0 or 1
+	base int
+
+	// the last converted position to continue from on long lines
+	lastLine, lastColumn, lastOffset, lastUnits int
+}
+
+func newPositionConverter(source string, lineStarts []int, config Config) *positionConverter {
+	c := &positionConverter{
+		source:     source,
+		lineStarts: lineStarts,
+		encoding:   config.PositionEncoding,
+		lastLine:   -1,
+	}
+	if config.OneBased {
+		c.base = 1
+	}
+	return c
+}
+
+// convert returns the line and column in the configured encoding and the byte offset of the position.
+func (c *positionConverter) convert(line, column int) (int, int, int) {
+	if line < 0 || line >= len(c.lineStarts) {
+		return line + c.base, column + c.base, len(c.source)
+	}
+	lineStart := c.lineStarts[line]
+	offset, units, remaining := lineStart, 0, column
+	if line == c.lastLine && column >= c.lastColumn {
+		offset, units, remaining = c.lastOffset, c.lastUnits, column-c.lastColumn
+	}
+
+	for remaining > 0 && offset < len(c.source) {
+		r, size := utf8.DecodeRuneInString(c.source[offset:])
+		if r == '\n' {
+			break
+		}
+		offset += size
+		if r == '\r' {
+			continue
+		}
+		remaining--
+		switch {
+		case c.encoding == PositionUTF8:
+			units += size
+		case c.encoding == PositionUTF16 && r >= 0x10000:
+			// surrogate pair
+			units += 2
+		default:
+			units++
+		}
+	}
+	if remaining == 0 {
+		c.lastLine, c.lastColumn, c.lastOffset, c.lastUnits = line, column, offset, units
+	}
+	// positions behind the end of the line, e.g. a missing token at the end of the file
+	units += remaining
+	offset += remaining
+
+	if c.encoding == PositionUTF8 {
+		// including carriage returns
+		units = offset - lineStart
+	}
+	return line + c.base, units + c.base, offset
+}
+
+func (c *positionConverter) token(token Token) Token {
+	token.Line, token.Column, token.Offset = c.convert(token.Line, token.Column)
+	token.EndLine, token.EndColumn, token.EndOffset = c.convert(token.EndLine, token.EndColumn)
+	return token
+}
+
+func (c *positionConverter) span(span Span) Span {
+	span.StartLine, span.StartColumn, _ = c.convert(span.StartLine, span.StartColumn)
+	span.EndLine, span.EndColumn, _ = c.convert(span.EndLine, span.EndColumn)
+	return span
+}
+
+func (c *positionConverter) diagnostic(diagnostic Diagnostic) Diagnostic {
+	diagnostic.StartLine, diagnostic.StartColumn, diagnostic.StartOffset = c.convert(diagnostic.StartLine, diagnostic.StartColumn)
+	diagnostic.EndLine, diagnostic.EndColumn, diagnostic.EndOffset = c.convert(diagnostic.EndLine, diagnostic.EndColumn)
+
+	related := make([]RelatedLocation, len(diagnostic.Related))
+	for i, r := range diagnostic.Related {
+		r.StartLine, r.StartColumn, r.StartOffset = c.convert(r.StartLine, r.StartColumn)
+		r.EndLine, r.EndColumn, r.EndOffset = c.convert(r.EndLine, r.EndColumn)
+		related[i] = r
+	}
+	diagnostic.Related = related
+
+	fixes := make([]Fix, len(diagnostic.Fixes))
+	for i, f := range diagnostic.Fixes {
+		edits := make([]Edit, len(f.Edits))
+		for j, e := range f.Edits {
+			e.StartLine, e.StartColumn, e.StartOffset = c.convert(e.StartLine, e.StartColumn)
+			e.EndLine, e.EndColumn, e.EndOffset = c.convert(e.EndLine, e.EndColumn)
+			edits[j] = e
+		}
+		f.Edits = edits
+		fixes[i] = f
+	}
+	diagnostic.Fixes = fixes
+	return diagnostic
+}
+
+func (c *positionConverter) object(object Object) Object {
+	object.Name = c.token(object.Name)
+	object.Span = c.span(object.Span)
+	properties := make([]Property, len(object.Properties))
+	for i, p := range object.Properties {
+		p.NameToken = c.token(p.NameToken)
+		p.Type = c.propertyType(p.Type)
+		p.Span = c.span(p.Span)
+		properties[i] = p
+	}
+	object.Properties = properties
+	return object
+}
+
+// propertyType returns a converted copy, because property types and symbols are shared between objects.
+func (c *positionConverter) propertyType(t *PropertyType) *PropertyType {
+	if t == nil {
+		return nil
+	}
+	converted := &PropertyType{
+		Token:   c.token(t.Token),
+		Generic: c.propertyType(t.Generic),
+		Span:    c.span(t.Span),
+	}
+	if t.Declaration != nil {
+		converted.Declaration = &Symbol{
+			Kind: t.Declaration.Kind,
+			Name: c.token(t.Declaration.Name),
+			Span: c.span(t.Declaration.Span),
+		}
+	}
+	return converted
+}
+
+// parserPos converts a position in the configured encoding back to a position of the parser.
+// Columns behind the end of the line are kept and columns inside a character are rounded up.
+func (c *positionConverter) parserPos(line, column int) (int, int) {
+	line, units := line-c.base, column-c.base
+	if line < 0 || line >= len(c.lineStarts) {
+		return line, units
+	}
+	column = 0
+	for offset := c.lineStarts[line]; units > 0 && offset < len(c.source); {
+		r, size := utf8.DecodeRuneInString(c.source[offset:])
+		if r == '\n' {
+			break
+		}
+		offset += size
+		switch {
+		case c.encoding == PositionUTF8:
+			units -= size
+		case r == '\r':
+			continue
+		case c.encoding == PositionUTF16 && r >= 0x10000:
+			units -= 2
+		default:
+			units--
+		}
+		if r != '\r' {
+			column++
+		}
+	}
+	if units > 0 {
+		column += units
+	}
+	return line, column
+}
+
+// positionSender converts all positions before passing them to the wrapped Sender.
+type positionSender struct {
+	Sender
+	converter *positionConverter
+}
+
+func (s *positionSender) SendDiagnostic(diagnostic Diagnostic) error {
+	return s.Sender.SendDiagnostic(s.converter.diagnostic(diagnostic))
+}
+
+func (s *positionSender) SendToken(token Token) error {
+	return s.Sender.SendToken(s.converter.token(token))
+}
+
+func (s *positionSender) SendSemanticToken(token SemanticToken) error {
+	token.Token = s.converter.token(token.Token)
+	return s.Sender.SendSemanticToken(token)
+}
+
+func (s *positionSender) SendObject(object Object) error {
+	return s.Sender.SendObject(s.converter.object(object))
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPositionEncoding(t *testing.T) {
+	source := "cge 0.5\r\n/* 😀\t*/ event a { b: strin }\n"
+	tests := []struct {
+		encoding PositionEncoding
+		oneBased bool
+		want     string
+		// position of the event name
+		wantName string
+	}{
+		{PositionUTF32, false, "1:21-1:26 @33-38", "1:14-1:15 @26-27"},
+		{PositionUTF16, false, "1:22-1:27 @33-38", "1:15-1:16 @26-27"},
+		{PositionUTF8, false, "1:24-1:29 @33-38", "1:17-1:18 @26-27"},
+		{PositionUTF16, true, "2:23-2:28 @33-38", "2:16-2:17 @26-27"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s one-based=%t", tt.encoding, tt.oneBased), func(t *testing.T) {
+			rec := &recorder{}
+			err := Parse(strings.NewReader(source), rec, Config{PositionEncoding: tt.encoding, OneBased: tt.oneBased, SendTokens: true})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(rec.diagnostics) != 1 {
+				t.Fatalf("Parse() diagnostics = %v, want 1", diagnosticSummary(rec.diagnostics))
+			}
+			d := rec.diagnostics[0]
+			got := fmt.Sprintf("%d:%d-%d:%d @%d-%d", d.StartLine, d.StartColumn, d.EndLine, d.EndColumn, d.StartOffset, d.EndOffset)
+			if got != tt.want {
+				t.Errorf("Parse() diagnostic at %s, want %s", got, tt.want)
+			}
+			name := rec.tokens[4]
+			got = fmt.Sprintf("%d:%d-%d:%d @%d-%d", name.Line, name.Column, name.EndLine, name.EndColumn, name.Offset, name.EndOffset)
+			if got != tt.wantName {
+				t.Errorf("Parse() token %q at %s, want %s", name.Lexeme, got, tt.wantName)
+			}
+		})
+	}
+}
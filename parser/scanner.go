@@ -1,41 +1,75 @@
 package parser
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
 
+// byteOrderMark is reported at the start of the file and an unexpected character anywhere else.
+const byteOrderMark = '\uFEFF'
+
+// scanner splits the source into tokens. Lexemes are substrings of the source and identifiers are interned,
+// so that scanning does not allocate per token.
 type scanner struct {
-	input *bufio.Scanner
+	source string
+	// byte offset of nextRune
+	offset int
+	// byte length of nextRune (0 at the end of the source)
+	nextSize int
 
 	tokenBuffer *tokenBuffer
 
-	line       int
-	column     int
-	tokenRunes []rune
+	line   int
+	column int
+	// byte offset and rune count of the current token
+	tokenStart  int
+	tokenLength int
+	// the source of the current token contains carriage returns or invalid UTF-8
+	tokenDirty bool
+	// position of the first invalid UTF-8 sequence in the current token
+	invalid   bool
+	invalidAt pos
 
 	nextRune rune
+
+	identifiers map[string]string
+
+	limits Limits
+	tokens int
+	// set when the size or token limit is exceeded
+	limitError *Token
+	// the error which occurred while reading the input
+	err error
+}
+
+// newScanner reads the complete input. A read error ends the input like EOF and is stored in err.
+func newScanner(input io.Reader, limits Limits) *scanner {
+	if limits.MaxBytes > 0 {
+		// enough to detect a rune crossing the limit
+		input = io.LimitReader(input, int64(limits.MaxBytes)+utf8.UTFMax)
+	}
+	data, err := io.ReadAll(input)
+	s := newStringScanner(string(data), limits)
+	s.err = err
+	return s
 }
 
-func newScanner(input io.Reader) *scanner {
-	inputScanner := bufio.NewScanner(input)
-	inputScanner.Split(bufio.ScanRunes)
+func newStringScanner(source string, limits Limits) *scanner {
 	s := &scanner{
-		input:       inputScanner,
+		source:      source,
 		tokenBuffer: newTokenBuffer(32),
-		tokenRunes:  make([]rune, 0, 32),
+		identifiers: make(map[string]string),
+		limits:      limits,
 	}
-	s.nextChar()
-	s.column = 0
-	s.tokenRunes = s.tokenRunes[:0]
+	s.readRune()
 	return s
 }
 
 func (s *scanner) nextToken() Token {
-	if s.tokenBuffer.length == 0 {
+	for s.tokenBuffer.length == 0 {
 		s.scanToken()
 	}
 	return s.tokenBuffer.pop()
@@ -50,20 +84,33 @@ func (s *scanner) peekToken(offset int) Token {
 }
 
 func (s *scanner) scanToken() {
-	c := s.nextChar()
+	if s.limitError != nil {
+		s.resetToken()
+		s.addToken(TTEOF)
+		return
+	}
 
 	for {
-		if c == '\000' {
+		// a NUL character in the source is not the end of the source
+		if s.atEnd() {
 			s.addToken(TTEOF)
 			return
 		}
+		c := s.nextChar()
+
+		if c == byteOrderMark && s.line == 0 && s.column == 1 && s.tokenStart == 0 {
+			span := s.tokenSpan()
+			fix := replaceSpanFix("Remove byte order mark", span, "")
+			s.newRecoverableError(span, CodeByteOrderMark, "CGE files must not start with a byte order mark", &fix)
+			s.resetToken()
+			continue
+		}
 
 		if unicode.IsSpace(c) {
-			s.tokenRunes = s.tokenRunes[:0]
+			s.resetToken()
 			if c == '\n' {
 				s.newLine()
 			}
-			c = s.nextChar()
 			continue
 		}
 
@@ -77,6 +124,8 @@ func (s *scanner) scanToken() {
 				s.comment()
 			} else if s.match('*') {
 				s.blockComment()
+			} else {
+				s.newErrorAtPrev(CodeInvalidCharacter, "unexpected character '/'. Comments start with '//' or '/*'")
 			}
 		case ':':
 			s.addToken(TTColon)
@@ -87,61 +136,53 @@ func (s *scanner) scanToken() {
 		case '>':
 			s.addToken(TTGreater)
 		default:
-			if isLowerAlpha(c) {
-				s.identifier()
+			if isLowerAlpha(c) || isUpperAlpha(c) {
+				s.identifier(isUpperAlpha(c))
 			} else if isDigit(c) {
 				err := s.versionNumber()
 				if err != nil {
-					s.newErrorAtNext(err.Error())
+					s.newErrorAtStart(CodeInvalidVersionNumber, err.Error())
 				}
+			} else if c == utf8.RuneError && s.invalid {
+				s.newErrorAtPrev(CodeInvalidEncoding, "invalid UTF-8 encoding")
+			} else if !unicode.IsPrint(c) {
+				s.newErrorAtPrev(CodeInvalidCharacter, fmt.Sprintf("unexpected character %U", c))
 			} else {
-				s.newErrorAtPrev(fmt.Sprintf("unexpected character '%c'", c))
+				s.newErrorAtPrev(CodeInvalidCharacter, fmt.Sprintf("unexpected character '%c'", c))
 			}
 		}
 		break
 	}
 }
 
-func (s *scanner) identifier() {
-	for isLowerAlphaNum(s.peekChar()) {
-		s.nextChar()
+// identifier scans an identifier or keyword. Identifiers containing uppercase letters are reported,
+// but scanned as identifiers to continue parsing.
+func (s *scanner) identifier(upper bool) {
+	for isLowerAlphaNum(s.peekChar()) || isUpperAlpha(s.peekChar()) {
+		if isUpperAlpha(s.nextChar()) {
+			upper = true
+		}
+	}
+
+	if max := s.limits.MaxIdentifierLength; max > 0 && s.tokenLength > max {
+		s.newErrorAtStart(CodeLimitExceeded, fmt.Sprintf("identifier exceeds the maximum length of %d characters", max))
+		return
 	}
 
-	name := string(s.tokenRunes)
-	switch name {
-	case "name":
-		s.addToken(TTGameName)
-	case "version", "cge":
-		s.addToken(TTCGEVersion)
-	case "config":
-		s.addToken(TTConfig)
-	case "event":
-		s.addToken(TTEvent)
-	case "command":
-		s.addToken(TTCommand)
-	case "type":
-		s.addToken(TTType)
-	case "enum":
-		s.addToken(TTEnum)
-	case "string":
-		s.addToken(TTString)
-	case "bool":
-		s.addToken(TTBool)
-	case "int", "int32":
-		s.addToken(TTInt32)
-	case "int64":
-		s.addToken(TTInt64)
-	case "float32":
-		s.addToken(TTFloat32)
-	case "float", "float64":
-		s.addToken(TTFloat64)
-	case "list":
-		s.addToken(TTList)
-	case "map":
-		s.addToken(TTMap)
-	default:
+	name := s.lexeme()
+	if upper {
+		span := s.tokenSpan()
+		snakeCase := camelToSnakeCase(name)
+		fix := replaceSpanFix(fmt.Sprintf("Rename to '%s'", snakeCase), span, snakeCase)
+		s.newRecoverableError(span, CodeInvalidIdentifier, fmt.Sprintf("identifiers must be snake_case: did you mean '%s'?", snakeCase), &fix)
 		s.addToken(TTIdentifier)
+		return
+	}
+	if tokenType, ok := keywordTypes[name]; ok {
+		s.addToken(tokenType)
+		return
 	}
+	s.addToken(TTIdentifier)
 }
 
 func (s *scanner) versionNumber() error {
@@ -165,18 +206,20 @@ func (s *scanner) versionNumber() error {
 }
 
 func (s *scanner) comment() {
-	for s.peekChar() != '\n' && s.peekChar() != '\000' {
+	for s.peekChar() != '\n' && !s.atEnd() {
 		s.nextChar()
 	}
-	s.addToken(TTComment)
+	s.addComment(s.line, s.column-s.tokenLength)
 }
 
 func (s *scanner) blockComment() {
 	line := s.line
 	column := s.column - 2
-	for s.peekChar() != '\000' {
+	terminated := false
+	for !s.atEnd() {
 		c := s.nextChar()
 		if c == '*' && s.match('/') {
+			terminated = true
 			break
 		}
 		if c == '\n' {
@@ -184,6 +227,20 @@ func (s *scanner) blockComment() {
 			s.column = 0
 		}
 	}
+	if !terminated && s.limitError == nil {
+		fix := insertFix("Insert '*/'", s.line, s.column, "*/")
+		s.newRecoverableError(Span{StartLine: line, StartColumn: column, EndLine: line, EndColumn: column + 2}, CodeUnterminatedComment, "unterminated block comment", &fix)
+	}
+	s.addComment(line, column)
+}
+
+// addComment adds a comment token after reporting the first invalid UTF-8 sequence in it.
+// Invalid UTF-8 in comments does not affect parsing.
+func (s *scanner) addComment(line, column int) {
+	if s.invalid {
+		at := s.invalidAt
+		s.newRecoverableError(Span{StartLine: at.line, StartColumn: at.column, EndLine: at.line, EndColumn: at.column + 1}, CodeInvalidEncoding, "invalid UTF-8 encoding", nil)
+	}
 	s.addTokenWithPos(TTComment, line, column)
 }
 
@@ -195,25 +252,53 @@ func (s *scanner) match(char rune) bool {
 	return true
 }
 
+// nextChar consumes nextRune and returns it. It returns '\000' at the end of the source,
+// which has to be told apart from a NUL character in the source with atEnd.
 func (s *scanner) nextChar() rune {
 	current := s.nextRune
-	for {
-		if !s.input.Scan() {
-			if err := s.input.Err(); err != nil {
-				panic(fmt.Errorf("failed to read input data: %w", err))
+	if s.nextSize == 0 {
+		return current
+	}
+	if current == utf8.RuneError && s.nextSize == 1 && !s.invalid {
+		s.invalid = true
+		s.invalidAt = pos{s.line, s.column}
+	}
+	s.offset += s.nextSize
+	s.column++
+	s.tokenLength++
+	s.readRune()
+	return current
+}
+
+// readRune decodes the rune at offset into nextRune. Carriage returns are skipped.
+func (s *scanner) readRune() {
+	for s.limitError == nil && s.offset < len(s.source) {
+		r, size := rune(s.source[s.offset]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(s.source[s.offset:])
+			if r == utf8.RuneError && size == 1 {
+				s.tokenDirty = true
 			}
-			s.nextRune = '\000'
-			return current
 		}
-		if s.input.Bytes()[0] != '\r' {
+		if max := s.limits.MaxBytes; max > 0 && s.offset+size > max {
+			s.exceedLimit(Span{StartLine: s.line, StartColumn: s.column, EndLine: s.line, EndColumn: s.column + 1}, fmt.Sprintf("input exceeds the maximum size of %d bytes", max))
 			break
 		}
+		if r != '\r' {
+			s.nextRune = r
+			s.nextSize = size
+			return
+		}
+		s.tokenDirty = true
+		s.offset += size
 	}
-	r, _ := utf8.DecodeRune(s.input.Bytes())
-	s.nextRune = r
-	s.column++
-	s.tokenRunes = append(s.tokenRunes, current)
-	return current
+	s.nextRune = '\000'
+	s.nextSize = 0
+}
+
+// atEnd reports whether all runes of the source have been consumed.
+func (s *scanner) atEnd() bool {
+	return s.nextSize == 0
 }
 
 func (s *scanner) peekChar() rune {
@@ -221,45 +306,135 @@ func (s *scanner) peekChar() rune {
 }
 
 func (s *scanner) addToken(tokenType TokenType) {
-	lexeme := string(s.tokenRunes)
-	s.addTokenWithPos(tokenType, s.line, s.column-len(lexeme))
+	s.addTokenWithPos(tokenType, s.line, s.column-s.tokenLength)
 }
 
 func (s *scanner) addTokenWithPos(tokenType TokenType, line, column int) {
-	lexeme := string(s.tokenRunes)
-	s.tokenBuffer.push(Token{
-		Line:   line,
-		Column: column,
-		Type:   tokenType,
-		Lexeme: lexeme,
+	lexeme := s.lexeme()
+	if tokenType == TTIdentifier {
+		lexeme = s.intern(lexeme)
+	}
+	s.push(Token{
+		Line:      line,
+		Column:    column,
+		EndLine:   s.line,
+		EndColumn: s.column,
+		Type:      tokenType,
+		Lexeme:    lexeme,
 	})
-	s.tokenRunes = s.tokenRunes[:0]
+	s.resetToken()
+}
+
+// lexeme returns the consumed runes of the current token.
+func (s *scanner) lexeme() string {
+	lexeme := s.source[s.tokenStart:s.offset]
+	if s.tokenDirty {
+		lexeme = strings.ReplaceAll(lexeme, "\r", "")
+		// replace every invalid byte with U+FFFD
+		lexeme = string([]rune(lexeme))
+	}
+	return lexeme
+}
+
+// intern returns the first occurrence of the identifier to share its memory with all later occurrences.
+func (s *scanner) intern(identifier string) string {
+	if interned, ok := s.identifiers[identifier]; ok {
+		return interned
+	}
+	s.identifiers[identifier] = identifier
+	return identifier
+}
+
+func (s *scanner) resetToken() {
+	s.tokenStart = s.offset
+	s.tokenLength = 0
+	// the next rune was already read
+	s.tokenDirty = s.nextRune == utf8.RuneError && s.nextSize == 1
+	s.invalid = false
+}
+
+// tokenSpan returns the span of the current token.
+func (s *scanner) tokenSpan() Span {
+	return Span{StartLine: s.line, StartColumn: s.column - s.tokenLength, EndLine: s.line, EndColumn: s.column}
+}
+
+// push adds the token to the buffer or EOF if the token limit is exceeded.
+func (s *scanner) push(token Token) {
+	if token.Type != TTEOF {
+		s.tokens++
+		if max := s.limits.MaxTokens; max > 0 && s.tokens > max {
+			s.exceedLimit(token.Span(), fmt.Sprintf("input exceeds the maximum of %d tokens", max))
+			token = Token{Line: token.Line, Column: token.Column, EndLine: token.Line, EndColumn: token.Column, Type: TTEOF}
+		}
+	}
+	s.tokenBuffer.push(token)
 }
 
 func (s *scanner) newLine() {
 	s.line++
 	s.column = 0
-	s.tokenRunes = s.tokenRunes[:0]
+	s.resetToken()
+}
+
+// newErrorAtStart reports an error spanning the current token.
+// Like all other tokens, the error token starts at the position where the scanner started scanning it.
+func (s *scanner) newErrorAtStart(code DiagnosticCode, message string) {
+	s.push(Token{
+		Line:      s.line,
+		Column:    s.column - s.tokenLength,
+		EndLine:   s.line,
+		EndColumn: s.column,
+		Type:      TTError,
+		Lexeme:    message,
+		errorCode: code,
+	})
+	s.resetToken()
 }
 
-func (s *scanner) newErrorAtNext(message string) {
-	s.tokenBuffer.push(Token{
-		Line:   s.line,
-		Column: s.column,
-		Type:   TTError,
-		Lexeme: message,
+func (s *scanner) newErrorAtPrev(code DiagnosticCode, message string) {
+	s.push(Token{
+		Line:      s.line,
+		Column:    s.column - 1,
+		EndLine:   s.line,
+		EndColumn: s.column,
+		Type:      TTError,
+		Lexeme:    message,
+		errorCode: code,
 	})
-	s.tokenRunes = s.tokenRunes[:0]
+	s.resetToken()
 }
 
-func (s *scanner) newErrorAtPrev(message string) {
-	s.tokenBuffer.push(Token{
-		Line:   s.line,
-		Column: s.column - 1,
-		Type:   TTError,
-		Lexeme: message,
+// newRecoverableError reports an error, which is otherwise ignored by the parser.
+// The error is added in front of the token it belongs to without resetting the current token.
+func (s *scanner) newRecoverableError(span Span, code DiagnosticCode, message string, fix *Fix) {
+	s.push(Token{
+		Line:        span.StartLine,
+		Column:      span.StartColumn,
+		EndLine:     span.EndLine,
+		EndColumn:   span.EndColumn,
+		Type:        TTError,
+		Lexeme:      message,
+		errorCode:   code,
+		recoverable: true,
+		fix:         fix,
 	})
-	s.tokenRunes = s.tokenRunes[:0]
+}
+
+// camelToSnakeCase converts camelCase and PascalCase to snake_case, e.g. 'HTTPServerID' to 'http_server_id'.
+func camelToSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if isUpperAlpha(r) {
+			nextLower := i+1 < len(runes) && runes[i+1] >= 'a' && runes[i+1] <= 'z'
+			if i > 0 && runes[i-1] != '_' && (!isUpperAlpha(runes[i-1]) || nextLower) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
 }
 
 func isDigit(char rune) bool {
@@ -270,6 +445,10 @@ func isLowerAlpha(char rune) bool {
 	return char >= 'a' && char <= 'z' || char == '_'
 }
 
+func isUpperAlpha(char rune) bool {
+	return char >= 'A' && char <= 'Z'
+}
+
 func isLowerAlphaNum(char rune) bool {
 	return isDigit(char) || isLowerAlpha(char)
 }
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"no trailing newline", "cge 0.5\nevent abc {}", []string{"0:0-0:3 cge", "0:4-0:7 0.5", "1:0-1:5 event", "1:6-1:9 abc", "1:10-1:11 {", "1:11-1:12 }", "1:12-1:12 "}},
+		{"carriage returns", "type a\r\n{\r}\r\n", []string{"0:0-0:4 type", "0:5-0:6 a", "1:0-1:1 {", "1:1-1:2 }", "2:0-2:0 "}},
+		{"multi-byte runes", "/* é😀 */ x\n", []string{"0:0-0:8 /* é😀 */", "0:9-0:10 x", "1:0-1:0 "}},
+		{"invalid utf-8", "// a\xffb\n", []string{"0:4-0:5 invalid UTF-8 encoding", "0:0-0:6 // a�b", "1:0-1:0 "}},
+		{"multi-line block comment", "/* a\nbc */ x", []string{"0:0-1:5 /* a\nbc */", "1:6-1:7 x", "1:7-1:7 "}},
+		{"invalid version number", "cge 1x", []string{"0:0-0:3 cge", "0:4-0:5 expected '.' after major version", "0:5-0:6 x", "0:6-0:6 "}},
+		{"lone slash", "a / b", []string{"0:0-0:1 a", "0:2-0:3 unexpected character '/'. Comments start with '//' or '/*'", "0:4-0:5 b", "0:5-0:5 "}},
+		{"unterminated block comment", "x /* a\nb", []string{"0:0-0:1 x", "0:2-0:4 unterminated block comment", "0:2-1:1 /* a\nb", "1:1-1:1 "}},
+		{"byte order mark", "\uFEFFcge", []string{"0:0-0:1 CGE files must not start with a byte order mark", "0:1-0:4 cge", "0:4-0:4 "}},
+		{"uppercase identifier", "playerId", []string{"0:0-0:8 identifiers must be snake_case: did you mean 'player_id'?", "0:0-0:8 playerId", "0:8-0:8 "}},
+		{"invalid utf-8 outside of comment", "a\xff", []string{"0:0-0:1 a", "0:1-0:2 invalid UTF-8 encoding", "0:2-0:2 "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScanner(strings.NewReader(tt.source), Limits{})
+			got := make([]string, 0, len(tt.want))
+			for {
+				token := s.nextToken()
+				got = append(got, fmt.Sprintf("%d:%d-%d:%d %s", token.Line, token.Column, token.EndLine, token.EndColumn, token.Lexeme))
+				if token.Type == TTEOF {
+					break
+				}
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("tokens = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScannerDiagnostics(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+		fix    string
+	}{
+		{"byte order mark", "\uFEFFcge 0.5\nevent a { x: int32 }\n", []string{"0:0 CGE0036"}, "Remove byte order mark"},
+		{"uppercase identifiers", "cge 0.5\nevent PlayerJoined { playerId: string }\n", []string{"1:21 CGE0037", "1:6 CGE0037"}, "Rename to 'player_joined'"},
+		{"invalid utf-8 in comment", "cge 0.5\n// \xff\nevent a { x: int32 }\n", []string{"1:3 CGE0035"}, ""},
+		{"unterminated block comment", "cge 0.5\nevent a { x: int32 }\n/* b", []string{"2:0 CGE0034", "2:4 CGE0007"}, "Insert '*/'"},
+		{"lone slash", "cge 0.5\nevent a { x: int32 / }\n", []string{"1:19 CGE0001"}, ""},
+		// the rest of the file is still scanned
+		{"NUL character", "cge 0.5\nevent a { x: int32 }\x00\nevent b { y: c }\n", []string{"1:20 CGE0001", "2:13 CGE0012"}, ""},
+		{"NUL character in comment", "cge 0.5\n// \x00 /* \x00 */\nevent b { y: c }\n", []string{"2:13 CGE0012"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			err := Parse(strings.NewReader(tt.source), rec, Config{DisableWarnings: true})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := diagnosticSummary(rec.diagnostics); !equalStrings(got, tt.want) {
+				t.Errorf("Parse() diagnostics = %v, want %v", got, tt.want)
+			}
+			if tt.fix != "" && (len(rec.diagnostics[0].Fixes) != 1 || rec.diagnostics[0].Fixes[0].Title != tt.fix) {
+				t.Errorf("fixes = %+v, want %q", rec.diagnostics[0].Fixes, tt.fix)
+			}
+		})
+	}
+}
+
+func TestNULCharacter(t *testing.T) {
+	rec := &recorder{}
+	err := Parse(strings.NewReader("cge 0.5\n\x00event a { x: int32 }\n"), rec, Config{SendTokens: true})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(rec.diagnostics) != 1 || rec.diagnostics[0].Message != "unexpected character U+0000" {
+		t.Fatalf("Parse() diagnostics = %+v, want unexpected character U+0000", rec.diagnostics)
+	}
+	if got := rec.tokens[len(rec.tokens)-1]; got.Type != TTCloseCurly || got.Line != 1 {
+		t.Errorf("last token = %+v, want '}' in line 1", got)
+	}
+}
+
+func TestCamelToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"playerId":     "player_id",
+		"PlayerJoined": "player_joined",
+		"HTTPServerID": "http_server_id",
+		"player2Name":  "player2_name",
+		"Player_Name":  "player_name",
+	}
+	for name, want := range tests {
+		if got := camelToSnakeCase(name); got != want {
+			t.Errorf("camelToSnakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// syntheticSchema returns a valid CGE file with n events, each using a type and an enum.
+func syntheticSchema(n int) string {
+	var b strings.Builder
+	b.WriteString("cge 0.5\n\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "// Player %d joined the game.\nevent player_joined_%d {\n\t// The new player.\n\tplayer: player_%d,\n\tteam: team_%d,\n\tscores: map<list<int64>>,\n}\n\n", i, i, i, i)
+		fmt.Fprintf(&b, "type player_%d {\n\tname: string,\n\tposition: type position_%d { x: float64, y: float64 },\n\tadmin: bool,\n}\n\n", i, i)
+		fmt.Fprintf(&b, "enum team_%d {\n\tred,\n\tblue,\n}\n\n", i)
+	}
+	return b.String()
+}
+
+func BenchmarkScanner(b *testing.B) {
+	source := syntheticSchema(1000)
+	b.SetBytes(int64(len(source)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := newScanner(strings.NewReader(source), Limits{})
+		for s.nextToken().Type != TTEOF {
+		}
+	}
+}
+
+type discardSender struct{}
+
+func (discardSender) SendMetadata(version string) error          { return nil }
+func (discardSender) SendDiagnostic(diagnostic Diagnostic) error { return nil }
+func (discardSender) SendToken(token Token) error {
+	return nil
+}
+func (discardSender) SendSemanticToken(token SemanticToken) error { return nil }
+func (discardSender) SendObject(object Object) error              { return nil }
+
+func BenchmarkParse(b *testing.B) {
+	source := syntheticSchema(1000)
+	b.SetBytes(int64(len(source)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := Parse(strings.NewReader(source), discardSender{}, Config{IncludeComments: true, SendTokens: true})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package parser
+
+import "sort"
+
+type SemanticTokenKind int32
+
+const (
+	SemanticGameName SemanticTokenKind = iota
+	SemanticCommand
+	SemanticEvent
+	SemanticType
+	SemanticEnum
+	SemanticEnumValue
+	SemanticProperty
+	// reference to an undefined type
+	SemanticUndefined
+)
+
+type SemanticTokenRole int32
+
+const (
+	SemanticDeclaration SemanticTokenRole = iota
+	SemanticReference
+)
+
+// SemanticToken classifies an identifier token.
+// Deprecated is only set on the game name of the deprecated 'name' field. The other deprecated constructs,
+// the 'version' field and alias types, are keywords and primitive types, which are not classified.
+type SemanticToken struct {
+	Token      Token
+	Kind       SemanticTokenKind
+	Role       SemanticTokenRole
+	Deprecated bool
+}
+
+// classify returns the semantic tokens of all identifiers in the objects ordered by position.
+func classify(gameName Token, objects []Object, accessedTypeIdentifiers []Token) []SemanticToken {
+	tokens := make([]SemanticToken, 0, len(objects)*4+len(accessedTypeIdentifiers))
+	if gameName.Type == TTIdentifier {
+		tokens = append(tokens, SemanticToken{
+			Token:      gameName,
+			Kind:       SemanticGameName,
+			Role:       SemanticDeclaration,
+			Deprecated: true,
+		})
+	}
+
+	typeKinds := make(map[string]SemanticTokenKind)
+	for _, o := range objects {
+		kind := objectSemanticKind(o.Type)
+		if o.Type != TTConfig {
+			tokens = append(tokens, SemanticToken{
+				Token: o.Name,
+				Kind:  kind,
+				Role:  SemanticDeclaration,
+			})
+		}
+		if _, ok := typeKinds[o.Name.Lexeme]; !ok && (o.Type == TTType || o.Type == TTEnum) {
+			typeKinds[o.Name.Lexeme] = kind
+		}
+
+		propertyKind := SemanticProperty
+		if o.Type == TTEnum {
+			propertyKind = SemanticEnumValue
+		}
+		for _, p := range o.Properties {
+			tokens = append(tokens, SemanticToken{
+				Token: p.NameToken,
+				Kind:  propertyKind,
+				Role:  SemanticDeclaration,
+			})
+		}
+	}
+
+	for _, id := range accessedTypeIdentifiers {
+		kind, ok := typeKinds[id.Lexeme]
+		if !ok {
+			kind = SemanticUndefined
+		}
+		tokens = append(tokens, SemanticToken{
+			Token: id,
+			Kind:  kind,
+			Role:  SemanticReference,
+		})
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		a, b := tokens[i].Token, tokens[j].Token
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return tokens
+}
+
+func objectSemanticKind(objectType TokenType) SemanticTokenKind {
+	switch objectType {
+	case TTCommand:
+		return SemanticCommand
+	case TTEvent:
+		return SemanticEvent
+	case TTEnum:
+		return SemanticEnum
+	}
+	return SemanticType
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var semanticKindNames = map[SemanticTokenKind]string{
+	SemanticGameName:  "game-name",
+	SemanticCommand:   "command",
+	SemanticEvent:     "event",
+	SemanticType:      "type",
+	SemanticEnum:      "enum",
+	SemanticEnumValue: "enum-value",
+	SemanticProperty:  "property",
+	SemanticUndefined: "undefined",
+}
+
+// semanticTokenSummary returns the tokens as "line:column lexeme kind role [deprecated]".
+func semanticTokenSummary(tokens []SemanticToken) []string {
+	summary := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		role := "declaration"
+		if t.Role == SemanticReference {
+			role = "reference"
+		}
+		s := fmt.Sprintf("%d:%d %s %s %s", t.Token.Line, t.Token.Column, t.Token.Lexeme, semanticKindNames[t.Kind], role)
+		if t.Deprecated {
+			s += " deprecated"
+		}
+		summary = append(summary, s)
+	}
+	return summary
+}
+
+func TestSemanticTokens(t *testing.T) {
+	source := `name my_game
+version 0.5
+config { mode: mode }
+command move { target: list<map<type pos { x: float64 }>> }
+event joined { team: team, ghost: unknwn, players: list<player>, score: int }
+type player { modes: map<list<mode>> }
+enum mode { solo, teams }
+enum team { red }
+`
+	want := []string{
+		// the game name is the only deprecated identifier
+		"0:5 my_game game-name declaration deprecated",
+		"2:9 mode property declaration",
+		"2:15 mode enum reference",
+		"3:8 move command declaration",
+		"3:15 target property declaration",
+		"3:37 pos type declaration",
+		"3:43 x property declaration",
+		"4:6 joined event declaration",
+		"4:15 team property declaration",
+		"4:21 team enum reference",
+		"4:27 ghost property declaration",
+		"4:34 unknwn undefined reference",
+		"4:42 players property declaration",
+		"4:56 player type reference",
+		"4:65 score property declaration",
+		"5:5 player type declaration",
+		"5:14 modes property declaration",
+		"5:30 mode enum reference",
+		"6:5 mode enum declaration",
+		"6:12 solo enum-value declaration",
+		"6:18 teams enum-value declaration",
+		"7:5 team enum declaration",
+		"7:12 red enum-value declaration",
+	}
+
+	rec := &fuzzSender{}
+	if err := Parse(strings.NewReader(source), rec, Config{SendSemanticTokens: true}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := semanticTokenSummary(rec.semanticTokens); !equalStrings(got, want) {
+		t.Errorf("Parse() semantic tokens =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	tree, err := parseTree(source, Config{})
+	if err != nil {
+		t.Fatalf("parseTree() error = %v", err)
+	}
+	if got := semanticTokenSummary(tree.SemanticTokens()); !equalStrings(got, want) {
+		t.Errorf("Tree.SemanticTokens() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
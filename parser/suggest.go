@@ -0,0 +1,90 @@
+package parser
+
+import "sort"
+
+// typeNameHints maps type names common in other languages to their CGE equivalent.
+var typeNameHints = map[string]string{
+	"str":     "string",
+	"text":    "string",
+	"boolean": "bool",
+	"integer": "int32",
+	"long":    "int64",
+	"double":  "float64",
+	"number":  "float64",
+}
+
+// suggestType returns a defined type or a primitive type, which was probably meant instead of name.
+func (p *parser) suggestType(name string) (string, bool) {
+	if hint, ok := typeNameHints[name]; ok {
+		return hint, true
+	}
+	candidates := make([]string, 0, len(p.symbols)+len(primitiveTypeNames))
+	for s := range p.symbols {
+		candidates = append(candidates, s)
+	}
+	candidates = append(candidates, primitiveTypeNames...)
+	return suggest(name, candidates)
+}
+
+// suggest returns the candidate closest to name if the difference is small enough to be a typo.
+// Ties are broken alphabetically.
+func suggest(name string, candidates []string) (string, bool) {
+	sorted := make([]string, len(candidates))
+	copy(sorted, candidates)
+	sort.Strings(sorted)
+
+	maxDistance := len([]rune(name)) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	best := ""
+	bestDistance := maxDistance + 1
+	for _, c := range sorted {
+		if c == name {
+			continue
+		}
+		d := editDistance(name, c)
+		if d < bestDistance && d < len([]rune(c)) {
+			best = c
+			bestDistance = d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions of adjacent characters needed to turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,26 @@
+package parser
+
+import "testing"
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		want       string
+	}{
+		{"playr", []string{"player", "players", "position"}, "player"},
+		{"evnet", declarationKeywords, "event"},
+		{"comand", declarationKeywords, "command"},
+		{"tpye", declarationKeywords, "type"},
+		{"strng", primitiveTypeNames, "string"},
+		{"xyz", primitiveTypeNames, ""},
+		{"a", []string{"b"}, ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := suggest(tt.name, tt.candidates)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("suggest(%q) = %q, %t, want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
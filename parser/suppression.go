@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Suppression comments silence warnings and infos with the listed codes:
+//
+//	// cge:ignore CGE0021 type-alias
+//	// cge:ignore-file deprecated-name-field
+//
+// 'cge:ignore' applies to diagnostics starting on the line of the comment if it follows other tokens on the same line,
+// otherwise to diagnostics starting on the following line.
+// 'cge:ignore-file' applies to the whole file. Errors cannot be suppressed. This includes lint rules configured with
+// the severity error, because the lint config of a project must not be overridden by single files.
+const (
+	suppressionPrefix     = "cge:ignore"
+	fileSuppressionPrefix = "cge:ignore-file"
+)
+
+// suppressionComment is a suppression comment with the line it applies to.
+type suppressionComment struct {
+	token Token
+	line  int
+}
+
+func newSuppressionComment(comment, previous Token) suppressionComment {
+	line := comment.Line + 1
+	if endLine, _ := tokenEnd(previous); previous.Lexeme != "" && endLine == comment.Line {
+		line = comment.Line
+	}
+	return suppressionComment{
+		token: comment,
+		line:  line,
+	}
+}
+
+type suppression struct {
+	comment Token
+	line    int
+	file    bool
+	codes   []DiagnosticCode
+	used    []bool
+}
+
+func isSuppressionComment(token Token) bool {
+	if token.Type != TTComment || !strings.HasPrefix(token.Lexeme, "//") {
+		return false
+	}
+	text := strings.TrimSpace(strings.TrimPrefix(token.Lexeme, "//"))
+	directive, _, _ := strings.Cut(text, " ")
+	return directive == suppressionPrefix || directive == fileSuppressionPrefix
+}
+
+// parseSuppression parses the suppression comment and returns warnings for invalid codes.
+func parseSuppression(c suppressionComment) (suppression, []Diagnostic) {
+	comment := c.token
+	fields := strings.FieldsFunc(strings.TrimPrefix(comment.Lexeme, "//"), func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ','
+	})
+	s := suppression{
+		comment: comment,
+		line:    c.line,
+		file:    fields[0] == fileSuppressionPrefix,
+	}
+	var warnings []Diagnostic
+	if len(fields) == 1 {
+		warnings = append(warnings, suppressionWarning(comment, CodeInvalidSuppression, fmt.Sprintf("expected diagnostic code after '%s'", fields[0])))
+	}
+	for _, f := range fields[1:] {
+		code, ok := ParseDiagnosticCode(f)
+		if !ok {
+			warnings = append(warnings, suppressionWarning(comment, CodeInvalidSuppression, fmt.Sprintf("unknown diagnostic code '%s'", f)))
+			continue
+		}
+		s.codes = append(s.codes, code)
+	}
+	s.used = make([]bool, len(s.codes))
+	return s, warnings
+}
+
+func (s *suppression) suppress(diagnostic Diagnostic) bool {
+	if !s.file && diagnostic.StartLine != s.line {
+		return false
+	}
+	for i, c := range s.codes {
+		if c == diagnostic.Code {
+			s.used[i] = true
+			return true
+		}
+	}
+	return false
+}
+
+// applySuppressions removes all warnings and infos silenced by one of the suppression comments
+// and appends warnings for invalid and unused suppressions.
+func applySuppressions(comments []suppressionComment, diagnostics []Diagnostic, config Config) []Diagnostic {
+	if len(comments) == 0 {
+		return diagnostics
+	}
+
+	suppressions := make([]suppression, 0, len(comments))
+	var warnings []Diagnostic
+	for _, c := range comments {
+		s, w := parseSuppression(c)
+		suppressions = append(suppressions, s)
+		warnings = append(warnings, w...)
+	}
+
+	result := make([]Diagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		suppressed := false
+		if d.Type != DiagnosticError {
+			for i := range suppressions {
+				if suppressions[i].suppress(d) {
+					suppressed = true
+					break
+				}
+			}
+		}
+		if !suppressed {
+			result = append(result, d)
+		}
+	}
+
+	for _, s := range suppressions {
+		unused := make([]string, 0, len(s.codes))
+		for i, c := range s.codes {
+			if !s.used[i] {
+				unused = append(unused, c.String())
+			}
+		}
+		if len(unused) == 0 {
+			continue
+		}
+		w := suppressionWarning(s.comment, CodeUnusedSuppression, fmt.Sprintf("suppression of %s does not suppress anything", strings.Join(unused, ", ")))
+		if len(unused) == len(s.codes) {
+			w.Fixes = []Fix{replaceFix("Remove suppression comment", s.comment, "")}
+		}
+		warnings = append(warnings, w)
+	}
+
+	if config.DisableWarnings {
+		return result
+	}
+	return append(result, warnings...)
+}
+
+func suppressionWarning(comment Token, code DiagnosticCode, message string) Diagnostic {
+	line, column := tokenEnd(comment)
+	return Diagnostic{
+		Type:        DiagnosticWarning,
+		Code:        code,
+		Message:     message,
+		StartLine:   comment.Line,
+		StartColumn: comment.Column,
+		EndLine:     line,
+		EndColumn:   column,
+	}
+}
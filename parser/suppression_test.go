@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const suppressionSource = `// cge:ignore-file deprecated-version-field
+version 0.5
+
+// cge:ignore type-alias
+event a {
+	x: int, // cge:ignore CGE0022
+	y: float,
+	// cge:ignore type-alias
+	z: int
+} // cge:ignore CGE0019 foo
+`
+
+func TestSuppressions(t *testing.T) {
+	want := []string{
+		"3:0 CGE0024",
+		"6:4 CGE0022",
+		"9:2 CGE0023",
+		"9:2 CGE0024",
+	}
+
+	rec := &recorder{}
+	err := Parse(strings.NewReader(suppressionSource), rec, Config{})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := diagnosticSummary(rec.diagnostics); !equalStrings(got, want) {
+		t.Errorf("Parse() diagnostics = %v, want %v", got, want)
+	}
+
+	tree, err := parseTree(suppressionSource, Config{})
+	if err != nil {
+		t.Fatalf("parseTree() error = %v", err)
+	}
+	if got := diagnosticSummary(tree.Diagnostics()); !equalStrings(got, want) {
+		t.Errorf("Tree.Diagnostics() = %v, want %v", got, want)
+	}
+}
+
+func diagnosticSummary(diagnostics []Diagnostic) []string {
+	summary := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		summary = append(summary, fmt.Sprintf("%d:%d %s", d.StartLine, d.StartColumn, d.Code))
+	}
+	sort.Strings(summary)
+	return summary
+}
+
+func equalStrings(a, b []string) bool {
+	return strings.Join(a, "\n") == strings.Join(b, "\n")
+}
+
+func TestSuppressedLintErrors(t *testing.T) {
+	source := "cge 0.5\nevent a {\n\tx: int, // cge:ignore type-alias\n}\n"
+	var config Config
+	if err := config.Lint.Set("type-alias", "error"); err != nil {
+		t.Fatal(err)
+	}
+	// lint rules with the severity error are not suppressed and the suppression is unused
+	want := []string{"2:4 CGE0022", "2:9 CGE0024"}
+
+	rec := &fuzzSender{}
+	err := Parse(strings.NewReader(source), rec, config)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := diagnosticSummary(rec.diagnostics); !equalStrings(got, want) {
+		t.Errorf("Parse() diagnostics = %v, want %v", got, want)
+	}
+	if len(rec.objects) != 0 {
+		t.Errorf("Parse() sent %d objects despite the lint error", len(rec.objects))
+	}
+
+	tree, err := parseTree(source, config)
+	if err != nil {
+		t.Fatalf("parseTree() error = %v", err)
+	}
+	if got := diagnosticSummary(tree.Diagnostics()); !equalStrings(got, want) {
+		t.Errorf("Tree.Diagnostics() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package parser
+
+// Symbol is an entry of the symbol table describing the declaration of a custom type.
+type Symbol struct {
+	// TTType or TTEnum
+	Kind TokenType
+	Name Token
+	// Span is the span of the declaration (Object.Span).
+	Span Span
+}
+
+// resolveReferences replaces all objects with copies whose custom property types point to their declaration.
+// The objects are copied because they may be shared with other trees.
+func (p *parser) resolveReferences() {
+	objects := make([]Object, len(p.objects))
+	for i, o := range p.objects {
+		properties := make([]Property, len(o.Properties))
+		for j, prop := range o.Properties {
+			prop.Type = p.resolve(prop.Type)
+			properties[j] = prop
+		}
+		o.Properties = properties
+		objects[i] = o
+	}
+	p.objects = objects
+}
+
+func (p *parser) resolve(propertyType *PropertyType) *PropertyType {
+	if propertyType == nil {
+		return nil
+	}
+	resolved := &PropertyType{
+		Token:   propertyType.Token,
+		Generic: p.resolve(propertyType.Generic),
+		Span:    propertyType.Span,
+	}
+	if propertyType.Token.Type == TTIdentifier {
+		if symbol, ok := p.symbols[propertyType.Token.Lexeme]; ok {
+			resolved.Declaration = &symbol
+		}
+	}
+	return resolved
+}
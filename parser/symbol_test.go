@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// declarationSummary describes the resolved declarations of a property type and its generics with their names and spans.
+func declarationSummary(t *PropertyType) string {
+	var parts []string
+	for ; t != nil; t = t.Generic {
+		if t.Declaration == nil {
+			parts = append(parts, t.Token.Lexeme+"->nil")
+			continue
+		}
+		kind := "type"
+		if t.Declaration.Kind == TTEnum {
+			kind = "enum"
+		}
+		d := t.Declaration
+		parts = append(parts, fmt.Sprintf("%s->%s %s %d:%d (%d:%d-%d:%d)", t.Token.Lexeme, kind, d.Name.Lexeme, d.Name.Line, d.Name.Column, d.Span.StartLine, d.Span.StartColumn, d.Span.EndLine, d.Span.EndColumn))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestResolveReferences(t *testing.T) {
+	source := "cge 0.5\nevent joined {\n\tplayers: list<map<player>>,\n\thome: type base { team: team },\n\tghost: unknown,\n\tscore: int32,\n}\ntype player { username: string }\nenum team { red }\n"
+	rec := &objectRecorder{}
+	err := Parse(strings.NewReader(source), rec, Config{ErrorTolerant: true})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[string]string{
+		"joined.players":  "list->nil, map->nil, player->type player 7:5 (7:0-7:32)",
+		"joined.home":     "base->type base 3:12 (3:7-3:31)",
+		"joined.ghost":    "unknown->nil",
+		"joined.score":    "int32->nil",
+		"base.team":       "team->enum team 8:5 (8:0-8:17)",
+		"player.username": "string->nil",
+	}
+	got := make(map[string]string)
+	for _, o := range rec.objects {
+		for _, p := range o.Properties {
+			if p.Type != nil {
+				got[o.Name.Lexeme+"."+p.Name] = declarationSummary(p.Type)
+			}
+		}
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("declarations of %s = %q, want %q", name, got[name], w)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d properties with types, want %d", len(got), len(want))
+	}
+}
@@ -5,6 +5,19 @@ type Token struct {
 	Lexeme string
 	Line   int
 	Column int
+	// exclusive end position of the token
+	EndLine   int
+	EndColumn int
+	// Offset and EndOffset are the byte offsets of the token in the file. They are only set in the output of Parse.
+	Offset    int
+	EndOffset int
+
+	// diagnostic code of TTError tokens
+	errorCode DiagnosticCode
+	// recoverable errors are reported but otherwise ignored by the parser
+	recoverable bool
+	// optional fix of TTError tokens
+	fix *Fix
 }
 
 type TokenType int
@@ -44,3 +57,27 @@ const (
 	TTError
 	TTEOF
 )
+
+// tokenEnd returns the exclusive end position of the token.
+func tokenEnd(token Token) (line, column int) {
+	return token.EndLine, token.EndColumn
+}
+
+// Span returns the range of the token.
+func (t Token) Span() Span {
+	return Span{StartLine: t.Line, StartColumn: t.Column, EndLine: t.EndLine, EndColumn: t.EndColumn}
+}
+
+// Span is the range of a token or declaration in the source. The end is exclusive.
+type Span struct {
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+}
+
+// to returns a span from the start of s to the end of end.
+func (s Span) to(end Span) Span {
+	s.EndLine, s.EndColumn = end.EndLine, end.EndColumn
+	return s
+}
@@ -23,10 +23,14 @@ const (
 type MsgType_Type int32
 
 const (
-	MsgType_METADATA   MsgType_Type = 0
-	MsgType_DIAGNOSTIC MsgType_Type = 1
-	MsgType_TOKEN      MsgType_Type = 2
-	MsgType_OBJECT     MsgType_Type = 3
+	MsgType_METADATA       MsgType_Type = 0
+	MsgType_DIAGNOSTIC     MsgType_Type = 1
+	MsgType_TOKEN          MsgType_Type = 2
+	MsgType_OBJECT         MsgType_Type = 3
+	MsgType_SEMANTIC_TOKEN MsgType_Type = 4
+	MsgType_DEFINITION     MsgType_Type = 5
+	MsgType_REFERENCES     MsgType_Type = 6
+	MsgType_HOVER          MsgType_Type = 7
 )
 
 // Enum value maps for MsgType_Type.
@@ -36,12 +40,20 @@ var (
 		1: "DIAGNOSTIC",
 		2: "TOKEN",
 		3: "OBJECT",
+		4: "SEMANTIC_TOKEN",
+		5: "DEFINITION",
+		6: "REFERENCES",
+		7: "HOVER",
 	}
 	MsgType_Type_value = map[string]int32{
-		"METADATA":   0,
-		"DIAGNOSTIC": 1,
-		"TOKEN":      2,
-		"OBJECT":     3,
+		"METADATA":       0,
+		"DIAGNOSTIC":     1,
+		"TOKEN":          2,
+		"OBJECT":         3,
+		"SEMANTIC_TOKEN": 4,
+		"DEFINITION":     5,
+		"REFERENCES":     6,
+		"HOVER":          7,
 	}
 )
 
@@ -121,6 +133,58 @@ func (Diagnostic_Type) EnumDescriptor() ([]byte, []int) {
 	return file_schema_proto_rawDescGZIP(), []int{2, 0}
 }
 
+type Diagnostic_Category int32
+
+const (
+	Diagnostic_SYNTAX      Diagnostic_Category = 0
+	Diagnostic_SEMANTIC    Diagnostic_Category = 1
+	Diagnostic_LINT        Diagnostic_Category = 2
+	Diagnostic_DEPRECATION Diagnostic_Category = 3
+)
+
+// Enum value maps for Diagnostic_Category.
+var (
+	Diagnostic_Category_name = map[int32]string{
+		0: "SYNTAX",
+		1: "SEMANTIC",
+		2: "LINT",
+		3: "DEPRECATION",
+	}
+	Diagnostic_Category_value = map[string]int32{
+		"SYNTAX":      0,
+		"SEMANTIC":    1,
+		"LINT":        2,
+		"DEPRECATION": 3,
+	}
+)
+
+func (x Diagnostic_Category) Enum() *Diagnostic_Category {
+	p := new(Diagnostic_Category)
+	*p = x
+	return p
+}
+
+func (x Diagnostic_Category) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Diagnostic_Category) Descriptor() protoreflect.EnumDescriptor {
+	return file_schema_proto_enumTypes[2].Descriptor()
+}
+
+func (Diagnostic_Category) Type() protoreflect.EnumType {
+	return &file_schema_proto_enumTypes[2]
+}
+
+func (x Diagnostic_Category) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Diagnostic_Category.Descriptor instead.
+func (Diagnostic_Category) EnumDescriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{2, 1}
+}
+
 type Token_Type int32
 
 const (
@@ -223,11 +287,11 @@ func (x Token_Type) String() string {
 }
 
 func (Token_Type) Descriptor() protoreflect.EnumDescriptor {
-	return file_schema_proto_enumTypes[2].Descriptor()
+	return file_schema_proto_enumTypes[3].Descriptor()
 }
 
 func (Token_Type) Type() protoreflect.EnumType {
-	return &file_schema_proto_enumTypes[2]
+	return &file_schema_proto_enumTypes[3]
 }
 
 func (x Token_Type) Number() protoreflect.EnumNumber {
@@ -239,6 +303,116 @@ func (Token_Type) EnumDescriptor() ([]byte, []int) {
 	return file_schema_proto_rawDescGZIP(), []int{3, 0}
 }
 
+type SemanticToken_Kind int32
+
+const (
+	SemanticToken_GAME_NAME  SemanticToken_Kind = 0
+	SemanticToken_COMMAND    SemanticToken_Kind = 1
+	SemanticToken_EVENT      SemanticToken_Kind = 2
+	SemanticToken_TYPE       SemanticToken_Kind = 3
+	SemanticToken_ENUM       SemanticToken_Kind = 4
+	SemanticToken_ENUM_VALUE SemanticToken_Kind = 5
+	SemanticToken_PROPERTY   SemanticToken_Kind = 6
+	SemanticToken_UNDEFINED  SemanticToken_Kind = 7
+)
+
+// Enum value maps for SemanticToken_Kind.
+var (
+	SemanticToken_Kind_name = map[int32]string{
+		0: "GAME_NAME",
+		1: "COMMAND",
+		2: "EVENT",
+		3: "TYPE",
+		4: "ENUM",
+		5: "ENUM_VALUE",
+		6: "PROPERTY",
+		7: "UNDEFINED",
+	}
+	SemanticToken_Kind_value = map[string]int32{
+		"GAME_NAME":  0,
+		"COMMAND":    1,
+		"EVENT":      2,
+		"TYPE":       3,
+		"ENUM":       4,
+		"ENUM_VALUE": 5,
+		"PROPERTY":   6,
+		"UNDEFINED":  7,
+	}
+)
+
+func (x SemanticToken_Kind) Enum() *SemanticToken_Kind {
+	p := new(SemanticToken_Kind)
+	*p = x
+	return p
+}
+
+func (x SemanticToken_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SemanticToken_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_schema_proto_enumTypes[4].Descriptor()
+}
+
+func (SemanticToken_Kind) Type() protoreflect.EnumType {
+	return &file_schema_proto_enumTypes[4]
+}
+
+func (x SemanticToken_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SemanticToken_Kind.Descriptor instead.
+func (SemanticToken_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{4, 0}
+}
+
+type SemanticToken_Role int32
+
+const (
+	SemanticToken_DECLARATION SemanticToken_Role = 0
+	SemanticToken_REFERENCE   SemanticToken_Role = 1
+)
+
+// Enum value maps for SemanticToken_Role.
+var (
+	SemanticToken_Role_name = map[int32]string{
+		0: "DECLARATION",
+		1: "REFERENCE",
+	}
+	SemanticToken_Role_value = map[string]int32{
+		"DECLARATION": 0,
+		"REFERENCE":   1,
+	}
+)
+
+func (x SemanticToken_Role) Enum() *SemanticToken_Role {
+	p := new(SemanticToken_Role)
+	*p = x
+	return p
+}
+
+func (x SemanticToken_Role) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SemanticToken_Role) Descriptor() protoreflect.EnumDescriptor {
+	return file_schema_proto_enumTypes[5].Descriptor()
+}
+
+func (SemanticToken_Role) Type() protoreflect.EnumType {
+	return &file_schema_proto_enumTypes[5]
+}
+
+func (x SemanticToken_Role) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SemanticToken_Role.Descriptor instead.
+func (SemanticToken_Role) EnumDescriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{4, 1}
+}
+
 type Object_Type int32
 
 const (
@@ -278,11 +452,11 @@ func (x Object_Type) String() string {
 }
 
 func (Object_Type) Descriptor() protoreflect.EnumDescriptor {
-	return file_schema_proto_enumTypes[3].Descriptor()
+	return file_schema_proto_enumTypes[6].Descriptor()
 }
 
 func (Object_Type) Type() protoreflect.EnumType {
-	return &file_schema_proto_enumTypes[3]
+	return &file_schema_proto_enumTypes[6]
 }
 
 func (x Object_Type) Number() protoreflect.EnumNumber {
@@ -291,7 +465,7 @@ func (x Object_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Object_Type.Descriptor instead.
 func (Object_Type) EnumDescriptor() ([]byte, []int) {
-	return file_schema_proto_rawDescGZIP(), []int{5, 0}
+	return file_schema_proto_rawDescGZIP(), []int{10, 0}
 }
 
 type Property_Type_DataType int32
@@ -348,11 +522,11 @@ func (x Property_Type_DataType) String() string {
 }
 
 func (Property_Type_DataType) Descriptor() protoreflect.EnumDescriptor {
-	return file_schema_proto_enumTypes[4].Descriptor()
+	return file_schema_proto_enumTypes[7].Descriptor()
 }
 
 func (Property_Type_DataType) Type() protoreflect.EnumType {
-	return &file_schema_proto_enumTypes[4]
+	return &file_schema_proto_enumTypes[7]
 }
 
 func (x Property_Type_DataType) Number() protoreflect.EnumNumber {
@@ -361,7 +535,7 @@ func (x Property_Type_DataType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Property_Type_DataType.Descriptor instead.
 func (Property_Type_DataType) EnumDescriptor() ([]byte, []int) {
-	return file_schema_proto_rawDescGZIP(), []int{6, 0, 0}
+	return file_schema_proto_rawDescGZIP(), []int{12, 0, 0}
 }
 
 type MsgType struct {
@@ -469,6 +643,13 @@ type Diagnostic struct {
 	Start *Pos `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
 	// exclusive
 	End *Pos `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	// stable identifier of the kind of diagnostic, e.g. CGE0012 (see `cge-parser explain CODE`)
+	Code string `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	// short name of the code, e.g. undefined-type
+	Name     string                `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+	Category Diagnostic_Category   `protobuf:"varint,7,opt,name=category,proto3,enum=cgeparser.Diagnostic_Category" json:"category,omitempty"`
+	Related  []*Diagnostic_Related `protobuf:"bytes,8,rep,name=related,proto3" json:"related,omitempty"`
+	Fixes    []*Diagnostic_Fix     `protobuf:"bytes,9,rep,name=fixes,proto3" json:"fixes,omitempty"`
 }
 
 func (x *Diagnostic) Reset() {
@@ -531,6 +712,41 @@ func (x *Diagnostic) GetEnd() *Pos {
 	return nil
 }
 
+func (x *Diagnostic) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetCategory() Diagnostic_Category {
+	if x != nil {
+		return x.Category
+	}
+	return Diagnostic_SYNTAX
+}
+
+func (x *Diagnostic) GetRelated() []*Diagnostic_Related {
+	if x != nil {
+		return x.Related
+	}
+	return nil
+}
+
+func (x *Diagnostic) GetFixes() []*Diagnostic_Fix {
+	if x != nil {
+		return x.Fixes
+	}
+	return nil
+}
+
 type Token struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -594,17 +810,20 @@ func (x *Token) GetPos() *Pos {
 	return nil
 }
 
-type Pos struct {
+type SemanticToken struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Line   int32 `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
-	Column int32 `protobuf:"varint,2,opt,name=column,proto3" json:"column,omitempty"`
+	Kind       SemanticToken_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=cgeparser.SemanticToken_Kind" json:"kind,omitempty"`
+	Role       SemanticToken_Role `protobuf:"varint,2,opt,name=role,proto3,enum=cgeparser.SemanticToken_Role" json:"role,omitempty"`
+	Deprecated bool               `protobuf:"varint,3,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	Lexeme     string             `protobuf:"bytes,4,opt,name=lexeme,proto3" json:"lexeme,omitempty"`
+	Pos        *Pos               `protobuf:"bytes,5,opt,name=pos,proto3" json:"pos,omitempty"`
 }
 
-func (x *Pos) Reset() {
-	*x = Pos{}
+func (x *SemanticToken) Reset() {
+	*x = SemanticToken{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_schema_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -612,13 +831,13 @@ func (x *Pos) Reset() {
 	}
 }
 
-func (x *Pos) String() string {
+func (x *SemanticToken) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Pos) ProtoMessage() {}
+func (*SemanticToken) ProtoMessage() {}
 
-func (x *Pos) ProtoReflect() protoreflect.Message {
+func (x *SemanticToken) ProtoReflect() protoreflect.Message {
 	mi := &file_schema_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -630,38 +849,61 @@ func (x *Pos) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Pos.ProtoReflect.Descriptor instead.
-func (*Pos) Descriptor() ([]byte, []int) {
+// Deprecated: Use SemanticToken.ProtoReflect.Descriptor instead.
+func (*SemanticToken) Descriptor() ([]byte, []int) {
 	return file_schema_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Pos) GetLine() int32 {
+func (x *SemanticToken) GetKind() SemanticToken_Kind {
 	if x != nil {
-		return x.Line
+		return x.Kind
 	}
-	return 0
+	return SemanticToken_GAME_NAME
 }
 
-func (x *Pos) GetColumn() int32 {
+func (x *SemanticToken) GetRole() SemanticToken_Role {
 	if x != nil {
-		return x.Column
+		return x.Role
 	}
-	return 0
+	return SemanticToken_DECLARATION
 }
 
-type Object struct {
+func (x *SemanticToken) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+func (x *SemanticToken) GetLexeme() string {
+	if x != nil {
+		return x.Lexeme
+	}
+	return ""
+}
+
+func (x *SemanticToken) GetPos() *Pos {
+	if x != nil {
+		return x.Pos
+	}
+	return nil
+}
+
+// Lines and columns are 0-based and columns count Unicode code points
+// unless configured otherwise with --position-encoding and --one-based.
+type Pos struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type       Object_Type `protobuf:"varint,1,opt,name=type,proto3,enum=cgeparser.Object_Type" json:"type,omitempty"`
-	Name       string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Properties []*Property `protobuf:"bytes,3,rep,name=properties,proto3" json:"properties,omitempty"`
-	Comment    *string     `protobuf:"bytes,4,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	Line   int32 `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Column int32 `protobuf:"varint,2,opt,name=column,proto3" json:"column,omitempty"`
+	// 0-based byte offset in the file (not set in query responses)
+	Offset int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
 }
 
-func (x *Object) Reset() {
-	*x = Object{}
+func (x *Pos) Reset() {
+	*x = Pos{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_schema_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -669,13 +911,13 @@ func (x *Object) Reset() {
 	}
 }
 
-func (x *Object) String() string {
+func (x *Pos) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Object) ProtoMessage() {}
+func (*Pos) ProtoMessage() {}
 
-func (x *Object) ProtoReflect() protoreflect.Message {
+func (x *Pos) ProtoReflect() protoreflect.Message {
 	mi := &file_schema_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -687,51 +929,45 @@ func (x *Object) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Object.ProtoReflect.Descriptor instead.
-func (*Object) Descriptor() ([]byte, []int) {
+// Deprecated: Use Pos.ProtoReflect.Descriptor instead.
+func (*Pos) Descriptor() ([]byte, []int) {
 	return file_schema_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Object) GetType() Object_Type {
+func (x *Pos) GetLine() int32 {
 	if x != nil {
-		return x.Type
+		return x.Line
 	}
-	return Object_CONFIG
+	return 0
 }
 
-func (x *Object) GetName() string {
+func (x *Pos) GetColumn() int32 {
 	if x != nil {
-		return x.Name
+		return x.Column
 	}
-	return ""
+	return 0
 }
 
-func (x *Object) GetProperties() []*Property {
+func (x *Pos) GetOffset() int32 {
 	if x != nil {
-		return x.Properties
+		return x.Offset
 	}
-	return nil
-}
-
-func (x *Object) GetComment() string {
-	if x != nil && x.Comment != nil {
-		return *x.Comment
-	}
-	return ""
+	return 0
 }
 
-type Property struct {
+type Location struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name    string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Type    *Property_Type `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
-	Comment *string        `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	// inclusive
+	Start *Pos `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	// exclusive
+	End *Pos `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
 }
 
-func (x *Property) Reset() {
-	*x = Property{}
+func (x *Location) Reset() {
+	*x = Location{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_schema_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -739,13 +975,13 @@ func (x *Property) Reset() {
 	}
 }
 
-func (x *Property) String() string {
+func (x *Location) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Property) ProtoMessage() {}
+func (*Location) ProtoMessage() {}
 
-func (x *Property) ProtoReflect() protoreflect.Message {
+func (x *Location) ProtoReflect() protoreflect.Message {
 	mi := &file_schema_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -757,44 +993,37 @@ func (x *Property) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Property.ProtoReflect.Descriptor instead.
-func (*Property) Descriptor() ([]byte, []int) {
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
 	return file_schema_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Property) GetName() string {
+func (x *Location) GetStart() *Pos {
 	if x != nil {
-		return x.Name
+		return x.Start
 	}
-	return ""
+	return nil
 }
 
-func (x *Property) GetType() *Property_Type {
+func (x *Location) GetEnd() *Pos {
 	if x != nil {
-		return x.Type
+		return x.End
 	}
 	return nil
 }
 
-func (x *Property) GetComment() string {
-	if x != nil && x.Comment != nil {
-		return *x.Comment
-	}
-	return ""
-}
-
-type Property_Type struct {
+// Response to `query --definition`. Empty if there is no symbol at the position.
+type Definition struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name    string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Type    Property_Type_DataType `protobuf:"varint,2,opt,name=type,proto3,enum=cgeparser.Property_Type_DataType" json:"type,omitempty"`
-	Generic *Property_Type         `protobuf:"bytes,3,opt,name=generic,proto3" json:"generic,omitempty"`
+	Name     string    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Location *Location `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
 }
 
-func (x *Property_Type) Reset() {
-	*x = Property_Type{}
+func (x *Definition) Reset() {
+	*x = Definition{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_schema_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -802,13 +1031,13 @@ func (x *Property_Type) Reset() {
 	}
 }
 
-func (x *Property_Type) String() string {
+func (x *Definition) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Property_Type) ProtoMessage() {}
+func (*Definition) ProtoMessage() {}
 
-func (x *Property_Type) ProtoReflect() protoreflect.Message {
+func (x *Definition) ProtoReflect() protoreflect.Message {
 	mi := &file_schema_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -820,47 +1049,655 @@ func (x *Property_Type) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Property_Type.ProtoReflect.Descriptor instead.
-func (*Property_Type) Descriptor() ([]byte, []int) {
-	return file_schema_proto_rawDescGZIP(), []int{6, 0}
+// Deprecated: Use Definition.ProtoReflect.Descriptor instead.
+func (*Definition) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *Property_Type) GetName() string {
+func (x *Definition) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *Property_Type) GetType() Property_Type_DataType {
+func (x *Definition) GetLocation() *Location {
 	if x != nil {
-		return x.Type
+		return x.Location
 	}
-	return Property_Type_STRING
+	return nil
 }
 
-func (x *Property_Type) GetGeneric() *Property_Type {
-	if x != nil {
-		return x.Generic
+// Response to `query --references`. The declaration is the first location. Empty if there is no symbol at the position.
+type References struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Locations []*Location `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+}
+
+func (x *References) Reset() {
+	*x = References{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-var File_schema_proto protoreflect.FileDescriptor
+func (x *References) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-var file_schema_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
-	0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x22, 0x75, 0x0a, 0x08, 0x6d, 0x73, 0x67,
-	0x5f, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2c, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
-	0x6d, 0x73, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
-	0x79, 0x70, 0x65, 0x22, 0x3b, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x4d,
-	0x45, 0x54, 0x41, 0x44, 0x41, 0x54, 0x41, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x49, 0x41,
-	0x47, 0x4e, 0x4f, 0x53, 0x54, 0x49, 0x43, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x4f, 0x4b,
-	0x45, 0x4e, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x10, 0x03,
-	0x22, 0x2b, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1f, 0x0a, 0x0b,
+func (*References) ProtoMessage() {}
+
+func (x *References) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use References.ProtoReflect.Descriptor instead.
+func (*References) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *References) GetLocations() []*Location {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+// Response to `query --hover`. Empty if there is no symbol at the position.
+type Hover struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the hovered identifier
+	Range *Location `protobuf:"bytes,1,opt,name=range,proto3" json:"range,omitempty"`
+	// the declaration in CGE syntax
+	Signature string `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	// the doc comment without comment markers
+	Doc string `protobuf:"bytes,3,opt,name=doc,proto3" json:"doc,omitempty"`
+}
+
+func (x *Hover) Reset() {
+	*x = Hover{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Hover) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hover) ProtoMessage() {}
+
+func (x *Hover) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hover.ProtoReflect.Descriptor instead.
+func (*Hover) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Hover) GetRange() *Location {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *Hover) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *Hover) GetDoc() string {
+	if x != nil {
+		return x.Doc
+	}
+	return ""
+}
+
+type Object struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type       Object_Type `protobuf:"varint,1,opt,name=type,proto3,enum=cgeparser.Object_Type" json:"type,omitempty"`
+	Name       string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Properties []*Property `protobuf:"bytes,3,rep,name=properties,proto3" json:"properties,omitempty"`
+	Comment    *string     `protobuf:"bytes,4,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	// true if the declaration contains syntax errors (only sent in error-tolerant mode)
+	Incomplete bool `protobuf:"varint,5,opt,name=incomplete,proto3" json:"incomplete,omitempty"`
+}
+
+func (x *Object) Reset() {
+	*x = Object{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Object) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Object) ProtoMessage() {}
+
+func (x *Object) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Object.ProtoReflect.Descriptor instead.
+func (*Object) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Object) GetType() Object_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Object_CONFIG
+}
+
+func (x *Object) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Object) GetProperties() []*Property {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *Object) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+func (x *Object) GetIncomplete() bool {
+	if x != nil {
+		return x.Incomplete
+	}
+	return false
+}
+
+// The declaration of a custom type.
+type Symbol struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// TYPE or ENUM
+	Kind Object_Type `protobuf:"varint,1,opt,name=kind,proto3,enum=cgeparser.Object_Type" json:"kind,omitempty"`
+	Name string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// inclusive
+	Start *Pos `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	// exclusive
+	End *Pos `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	// the whole declaration from the keyword to the closing '}' (without offsets)
+	Span *Location `protobuf:"bytes,5,opt,name=span,proto3" json:"span,omitempty"`
+}
+
+func (x *Symbol) Reset() {
+	*x = Symbol{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Symbol) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Symbol) ProtoMessage() {}
+
+func (x *Symbol) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Symbol.ProtoReflect.Descriptor instead.
+func (*Symbol) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Symbol) GetKind() Object_Type {
+	if x != nil {
+		return x.Kind
+	}
+	return Object_CONFIG
+}
+
+func (x *Symbol) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Symbol) GetStart() *Pos {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Symbol) GetEnd() *Pos {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Symbol) GetSpan() *Location {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+type Property struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type    *Property_Type `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Comment *string        `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+}
+
+func (x *Property) Reset() {
+	*x = Property{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Property) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Property) ProtoMessage() {}
+
+func (x *Property) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Property.ProtoReflect.Descriptor instead.
+func (*Property) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Property) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Property) GetType() *Property_Type {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *Property) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+// Another location, which is part of the problem, e.g. the first declaration of a duplicate.
+type Diagnostic_Related struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Msg string `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	// inclusive
+	Start *Pos `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	// exclusive
+	End *Pos `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *Diagnostic_Related) Reset() {
+	*x = Diagnostic_Related{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Diagnostic_Related) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic_Related) ProtoMessage() {}
+
+func (x *Diagnostic_Related) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic_Related.ProtoReflect.Descriptor instead.
+func (*Diagnostic_Related) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *Diagnostic_Related) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *Diagnostic_Related) GetStart() *Pos {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Diagnostic_Related) GetEnd() *Pos {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+// A machine-applicable change to the source, which resolves the problem.
+type Diagnostic_Fix struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Title string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Edits []*Diagnostic_Fix_Edit `protobuf:"bytes,2,rep,name=edits,proto3" json:"edits,omitempty"`
+	// The fix does not change the meaning of the file and can be applied without review.
+	Safe bool `protobuf:"varint,3,opt,name=safe,proto3" json:"safe,omitempty"`
+}
+
+func (x *Diagnostic_Fix) Reset() {
+	*x = Diagnostic_Fix{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Diagnostic_Fix) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic_Fix) ProtoMessage() {}
+
+func (x *Diagnostic_Fix) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic_Fix.ProtoReflect.Descriptor instead.
+func (*Diagnostic_Fix) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{2, 1}
+}
+
+func (x *Diagnostic_Fix) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Diagnostic_Fix) GetEdits() []*Diagnostic_Fix_Edit {
+	if x != nil {
+		return x.Edits
+	}
+	return nil
+}
+
+func (x *Diagnostic_Fix) GetSafe() bool {
+	if x != nil {
+		return x.Safe
+	}
+	return false
+}
+
+type Diagnostic_Fix_Edit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// inclusive
+	Start *Pos `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	// exclusive
+	End *Pos `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	// replacement of the text between start and end
+	Text string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *Diagnostic_Fix_Edit) Reset() {
+	*x = Diagnostic_Fix_Edit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Diagnostic_Fix_Edit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic_Fix_Edit) ProtoMessage() {}
+
+func (x *Diagnostic_Fix_Edit) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic_Fix_Edit.ProtoReflect.Descriptor instead.
+func (*Diagnostic_Fix_Edit) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{2, 1, 0}
+}
+
+func (x *Diagnostic_Fix_Edit) GetStart() *Pos {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Diagnostic_Fix_Edit) GetEnd() *Pos {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Diagnostic_Fix_Edit) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type Property_Type struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type    Property_Type_DataType `protobuf:"varint,2,opt,name=type,proto3,enum=cgeparser.Property_Type_DataType" json:"type,omitempty"`
+	Generic *Property_Type         `protobuf:"bytes,3,opt,name=generic,proto3" json:"generic,omitempty"`
+	// only present for resolved CUSTOM types
+	Declaration *Symbol `protobuf:"bytes,4,opt,name=declaration,proto3" json:"declaration,omitempty"`
+}
+
+func (x *Property_Type) Reset() {
+	*x = Property_Type{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_schema_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Property_Type) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Property_Type) ProtoMessage() {}
+
+func (x *Property_Type) ProtoReflect() protoreflect.Message {
+	mi := &file_schema_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Property_Type.ProtoReflect.Descriptor instead.
+func (*Property_Type) Descriptor() ([]byte, []int) {
+	return file_schema_proto_rawDescGZIP(), []int{12, 0}
+}
+
+func (x *Property_Type) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Property_Type) GetType() Property_Type_DataType {
+	if x != nil {
+		return x.Type
+	}
+	return Property_Type_STRING
+}
+
+func (x *Property_Type) GetGeneric() *Property_Type {
+	if x != nil {
+		return x.Generic
+	}
+	return nil
+}
+
+func (x *Property_Type) GetDeclaration() *Symbol {
+	if x != nil {
+		return x.Declaration
+	}
+	return nil
+}
+
+var File_schema_proto protoreflect.FileDescriptor
+
+var file_schema_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
+	0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x22, 0xb4, 0x01, 0x0a, 0x08, 0x6d, 0x73,
+	0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2c, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x6d, 0x73, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x22, 0x7a, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08,
+	0x4d, 0x45, 0x54, 0x41, 0x44, 0x41, 0x54, 0x41, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x49,
+	0x41, 0x47, 0x4e, 0x4f, 0x53, 0x54, 0x49, 0x43, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x4f,
+	0x4b, 0x45, 0x4e, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x4f, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x10,
+	0x03, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x45, 0x4d, 0x41, 0x4e, 0x54, 0x49, 0x43, 0x5f, 0x54, 0x4f,
+	0x4b, 0x45, 0x4e, 0x10, 0x04, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x49, 0x54,
+	0x49, 0x4f, 0x4e, 0x10, 0x05, 0x12, 0x0e, 0x0a, 0x0a, 0x52, 0x45, 0x46, 0x45, 0x52, 0x45, 0x4e,
+	0x43, 0x45, 0x53, 0x10, 0x06, 0x12, 0x09, 0x0a, 0x05, 0x48, 0x4f, 0x56, 0x45, 0x52, 0x10, 0x07,
+	0x22, 0x2b, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1f, 0x0a, 0x0b,
 	0x63, 0x67, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0a, 0x63, 0x67, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xc0, 0x01,
+	0x09, 0x52, 0x0a, 0x63, 0x67, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x80, 0x06,
 	0x0a, 0x0a, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x12, 0x2e, 0x0a, 0x04,
 	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x63, 0x67, 0x65,
 	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69,
@@ -870,9 +1707,45 @@ var file_schema_proto_rawDesc = []byte{
 	0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73, 0x52, 0x05, 0x73,
 	0x74, 0x61, 0x72, 0x74, 0x12, 0x20, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x6f,
-	0x73, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x28, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08,
-	0x0a, 0x04, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x41, 0x52, 0x4e,
-	0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02,
+	0x73, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3a,
+	0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x69, 0x61,
+	0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x2e, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79,
+	0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x37, 0x0a, 0x07, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x65, 0x64, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x63, 0x67,
+	0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74,
+	0x69, 0x63, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x52, 0x07, 0x72, 0x65, 0x6c, 0x61,
+	0x74, 0x65, 0x64, 0x12, 0x2f, 0x0a, 0x05, 0x66, 0x69, 0x78, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44,
+	0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x2e, 0x46, 0x69, 0x78, 0x52, 0x05, 0x66,
+	0x69, 0x78, 0x65, 0x73, 0x1a, 0x63, 0x0a, 0x07, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x12,
+	0x10, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73,
+	0x67, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x20, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x50, 0x6f, 0x73, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x1a, 0xc9, 0x01, 0x0a, 0x03, 0x46, 0x69,
+	0x78, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x34, 0x0a, 0x05, 0x65, 0x64, 0x69, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x2e, 0x46, 0x69,
+	0x78, 0x2e, 0x45, 0x64, 0x69, 0x74, 0x52, 0x05, 0x65, 0x64, 0x69, 0x74, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x73, 0x61, 0x66, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x61, 0x66,
+	0x65, 0x1a, 0x62, 0x0a, 0x04, 0x45, 0x64, 0x69, 0x74, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12,
+	0x20, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63,
+	0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73, 0x52, 0x03, 0x65, 0x6e,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x28, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a,
+	0x04, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x41, 0x52, 0x4e, 0x49,
+	0x4e, 0x47, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x22,
+	0x3f, 0x0a, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x0a, 0x0a, 0x06, 0x53,
+	0x59, 0x4e, 0x54, 0x41, 0x58, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x45, 0x4d, 0x41, 0x4e,
+	0x54, 0x49, 0x43, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x49, 0x4e, 0x54, 0x10, 0x02, 0x12,
+	0x0f, 0x0a, 0x0b, 0x44, 0x45, 0x50, 0x52, 0x45, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x03,
 	0x22, 0xe8, 0x03, 0x0a, 0x05, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x79,
 	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61,
 	0x72, 0x73, 0x65, 0x72, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52,
@@ -903,51 +1776,113 @@ var file_schema_proto_rawDesc = []byte{
 	0x72, 0x65, 0x61, 0x74, 0x65, 0x72, 0x10, 0x15, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x54, 0x4c, 0x65,
 	0x73, 0x73, 0x10, 0x16, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x54, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
 	0x74, 0x10, 0x17, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x54, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x18,
-	0x12, 0x09, 0x0a, 0x05, 0x54, 0x54, 0x45, 0x4f, 0x46, 0x10, 0x19, 0x22, 0x31, 0x0a, 0x03, 0x50,
-	0x6f, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x22, 0xe8,
-	0x01, 0x0a, 0x06, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72,
-	0x73, 0x65, 0x72, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52,
-	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x0a, 0x70, 0x72, 0x6f,
-	0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
-	0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72,
-	0x74, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1d,
-	0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48,
-	0x00, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x22, 0x3e, 0x0a,
-	0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10,
-	0x00, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x10, 0x01, 0x12, 0x09,
-	0x0a, 0x05, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x59, 0x50,
-	0x45, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x45, 0x4e, 0x55, 0x4d, 0x10, 0x04, 0x42, 0x0a, 0x0a,
-	0x08, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x80, 0x03, 0x0a, 0x08, 0x50, 0x72,
-	0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x04, 0x74, 0x79,
-	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61,
-	0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x2e, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d,
-	0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6f, 0x6d,
-	0x6d, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x1a, 0x86, 0x02, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x09, 0x0a, 0x05, 0x54, 0x54, 0x45, 0x4f, 0x46, 0x10, 0x19, 0x22, 0xe7, 0x02, 0x0a, 0x0d,
+	0x53, 0x65, 0x6d, 0x61, 0x6e, 0x74, 0x69, 0x63, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x31, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x67,
+	0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6d, 0x61, 0x6e, 0x74, 0x69, 0x63,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64,
+	0x12, 0x31, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d,
+	0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x6d, 0x61, 0x6e,
+	0x74, 0x69, 0x63, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x04, 0x72,
+	0x6f, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61, 0x74, 0x65,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x64, 0x65, 0x70, 0x72, 0x65, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x78, 0x65, 0x6d, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x65, 0x78, 0x65, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x03, 0x70,
+	0x6f, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73, 0x52, 0x03, 0x70, 0x6f, 0x73, 0x22, 0x6e, 0x0a,
+	0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x0d, 0x0a, 0x09, 0x47, 0x41, 0x4d, 0x45, 0x5f, 0x4e, 0x41,
+	0x4d, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x10,
+	0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04,
+	0x54, 0x59, 0x50, 0x45, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x45, 0x4e, 0x55, 0x4d, 0x10, 0x04,
+	0x12, 0x0e, 0x0a, 0x0a, 0x45, 0x4e, 0x55, 0x4d, 0x5f, 0x56, 0x41, 0x4c, 0x55, 0x45, 0x10, 0x05,
+	0x12, 0x0c, 0x0a, 0x08, 0x50, 0x52, 0x4f, 0x50, 0x45, 0x52, 0x54, 0x59, 0x10, 0x06, 0x12, 0x0d,
+	0x0a, 0x09, 0x55, 0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x07, 0x22, 0x26, 0x0a,
+	0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x44, 0x45, 0x43, 0x4c, 0x41, 0x52, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x52, 0x45, 0x46, 0x45, 0x52, 0x45,
+	0x4e, 0x43, 0x45, 0x10, 0x01, 0x22, 0x49, 0x0a, 0x03, 0x50, 0x6f, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x22, 0x52, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x67,
+	0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x12, 0x20, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73, 0x52,
+	0x03, 0x65, 0x6e, 0x64, 0x22, 0x51, 0x0a, 0x0a, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x3f, 0x0a, 0x0a, 0x52, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x62, 0x0a, 0x05, 0x48, 0x6f, 0x76, 0x65,
+	0x72, 0x12, 0x29, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x4c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x6f,
+	0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x64, 0x6f, 0x63, 0x22, 0x88, 0x02, 0x0a,
+	0x06, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65,
+	0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x67,
+	0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79,
+	0x52, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x07,
+	0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x0a, 0x69,
+	0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x22, 0x3e, 0x0a, 0x04, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x00, 0x12,
+	0x0b, 0x0a, 0x07, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e, 0x44, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05,
+	0x45, 0x56, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x59, 0x50, 0x45, 0x10,
+	0x03, 0x12, 0x08, 0x0a, 0x04, 0x45, 0x4e, 0x55, 0x4d, 0x10, 0x04, 0x42, 0x0a, 0x0a, 0x08, 0x5f,
+	0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0xb9, 0x01, 0x0a, 0x06, 0x53, 0x79, 0x6d, 0x62,
+	0x6f, 0x6c, 0x12, 0x2a, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x16, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x6f,
+	0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x20, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x50, 0x6f, 0x73, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x27, 0x0a, 0x04, 0x73, 0x70,
+	0x61, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x73,
+	0x70, 0x61, 0x6e, 0x22, 0xb5, 0x03, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79,
 	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x21, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50,
-	0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x2e, 0x44, 0x61, 0x74,
-	0x61, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x67,
-	0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50,
+	0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x1d, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x88, 0x01,
+	0x01, 0x1a, 0xbb, 0x02, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x35,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x63,
 	0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74,
-	0x79, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x22,
-	0x7f, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x53,
-	0x54, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f, 0x4f, 0x4c, 0x10,
-	0x01, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x4e, 0x54, 0x33, 0x32, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05,
-	0x49, 0x4e, 0x54, 0x36, 0x34, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4c, 0x4f, 0x41, 0x54,
-	0x33, 0x32, 0x10, 0x04, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x36, 0x34, 0x10,
-	0x05, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x41, 0x50, 0x10, 0x06, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x49,
-	0x53, 0x54, 0x10, 0x07, 0x12, 0x0e, 0x0a, 0x0a, 0x45, 0x4e, 0x55, 0x4d, 0x5f, 0x56, 0x41, 0x4c,
-	0x55, 0x45, 0x10, 0x08, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x55, 0x53, 0x54, 0x4f, 0x4d, 0x10, 0x09,
-	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x13, 0x5a, 0x11,
-	0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d,
-	0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x79, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x2e, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x07, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x12, 0x33, 0x0a, 0x0b, 0x64, 0x65, 0x63,
+	0x6c, 0x61, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x63, 0x67, 0x65, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x53, 0x79, 0x6d, 0x62, 0x6f,
+	0x6c, 0x52, 0x0b, 0x64, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x7f,
+	0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54,
+	0x52, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f, 0x4f, 0x4c, 0x10, 0x01,
+	0x12, 0x09, 0x0a, 0x05, 0x49, 0x4e, 0x54, 0x33, 0x32, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x49,
+	0x4e, 0x54, 0x36, 0x34, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x33,
+	0x32, 0x10, 0x04, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x36, 0x34, 0x10, 0x05,
+	0x12, 0x07, 0x0a, 0x03, 0x4d, 0x41, 0x50, 0x10, 0x06, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x49, 0x53,
+	0x54, 0x10, 0x07, 0x12, 0x0e, 0x0a, 0x0a, 0x45, 0x4e, 0x55, 0x4d, 0x5f, 0x56, 0x41, 0x4c, 0x55,
+	0x45, 0x10, 0x08, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x55, 0x53, 0x54, 0x4f, 0x4d, 0x10, 0x09, 0x42,
+	0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x13, 0x5a, 0x11, 0x2e,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -962,40 +1897,73 @@ func file_schema_proto_rawDescGZIP() []byte {
 	return file_schema_proto_rawDescData
 }
 
-var file_schema_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_schema_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_schema_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_schema_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_schema_proto_goTypes = []interface{}{
 	(MsgType_Type)(0),           // 0: cgeparser.msg_type.Type
 	(Diagnostic_Type)(0),        // 1: cgeparser.Diagnostic.Type
-	(Token_Type)(0),             // 2: cgeparser.Token.Type
-	(Object_Type)(0),            // 3: cgeparser.Object.Type
-	(Property_Type_DataType)(0), // 4: cgeparser.Property.Type.DataType
-	(*MsgType)(nil),             // 5: cgeparser.msg_type
-	(*Metadata)(nil),            // 6: cgeparser.Metadata
-	(*Diagnostic)(nil),          // 7: cgeparser.Diagnostic
-	(*Token)(nil),               // 8: cgeparser.Token
-	(*Pos)(nil),                 // 9: cgeparser.Pos
-	(*Object)(nil),              // 10: cgeparser.Object
-	(*Property)(nil),            // 11: cgeparser.Property
-	(*Property_Type)(nil),       // 12: cgeparser.Property.Type
+	(Diagnostic_Category)(0),    // 2: cgeparser.Diagnostic.Category
+	(Token_Type)(0),             // 3: cgeparser.Token.Type
+	(SemanticToken_Kind)(0),     // 4: cgeparser.SemanticToken.Kind
+	(SemanticToken_Role)(0),     // 5: cgeparser.SemanticToken.Role
+	(Object_Type)(0),            // 6: cgeparser.Object.Type
+	(Property_Type_DataType)(0), // 7: cgeparser.Property.Type.DataType
+	(*MsgType)(nil),             // 8: cgeparser.msg_type
+	(*Metadata)(nil),            // 9: cgeparser.Metadata
+	(*Diagnostic)(nil),          // 10: cgeparser.Diagnostic
+	(*Token)(nil),               // 11: cgeparser.Token
+	(*SemanticToken)(nil),       // 12: cgeparser.SemanticToken
+	(*Pos)(nil),                 // 13: cgeparser.Pos
+	(*Location)(nil),            // 14: cgeparser.Location
+	(*Definition)(nil),          // 15: cgeparser.Definition
+	(*References)(nil),          // 16: cgeparser.References
+	(*Hover)(nil),               // 17: cgeparser.Hover
+	(*Object)(nil),              // 18: cgeparser.Object
+	(*Symbol)(nil),              // 19: cgeparser.Symbol
+	(*Property)(nil),            // 20: cgeparser.Property
+	(*Diagnostic_Related)(nil),  // 21: cgeparser.Diagnostic.Related
+	(*Diagnostic_Fix)(nil),      // 22: cgeparser.Diagnostic.Fix
+	(*Diagnostic_Fix_Edit)(nil), // 23: cgeparser.Diagnostic.Fix.Edit
+	(*Property_Type)(nil),       // 24: cgeparser.Property.Type
 }
 var file_schema_proto_depIdxs = []int32{
 	0,  // 0: cgeparser.msg_type.type:type_name -> cgeparser.msg_type.Type
 	1,  // 1: cgeparser.Diagnostic.type:type_name -> cgeparser.Diagnostic.Type
-	9,  // 2: cgeparser.Diagnostic.start:type_name -> cgeparser.Pos
-	9,  // 3: cgeparser.Diagnostic.end:type_name -> cgeparser.Pos
-	2,  // 4: cgeparser.Token.type:type_name -> cgeparser.Token.Type
-	9,  // 5: cgeparser.Token.pos:type_name -> cgeparser.Pos
-	3,  // 6: cgeparser.Object.type:type_name -> cgeparser.Object.Type
-	11, // 7: cgeparser.Object.properties:type_name -> cgeparser.Property
-	12, // 8: cgeparser.Property.type:type_name -> cgeparser.Property.Type
-	4,  // 9: cgeparser.Property.Type.type:type_name -> cgeparser.Property.Type.DataType
-	12, // 10: cgeparser.Property.Type.generic:type_name -> cgeparser.Property.Type
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	13, // 2: cgeparser.Diagnostic.start:type_name -> cgeparser.Pos
+	13, // 3: cgeparser.Diagnostic.end:type_name -> cgeparser.Pos
+	2,  // 4: cgeparser.Diagnostic.category:type_name -> cgeparser.Diagnostic.Category
+	21, // 5: cgeparser.Diagnostic.related:type_name -> cgeparser.Diagnostic.Related
+	22, // 6: cgeparser.Diagnostic.fixes:type_name -> cgeparser.Diagnostic.Fix
+	3,  // 7: cgeparser.Token.type:type_name -> cgeparser.Token.Type
+	13, // 8: cgeparser.Token.pos:type_name -> cgeparser.Pos
+	4,  // 9: cgeparser.SemanticToken.kind:type_name -> cgeparser.SemanticToken.Kind
+	5,  // 10: cgeparser.SemanticToken.role:type_name -> cgeparser.SemanticToken.Role
+	13, // 11: cgeparser.SemanticToken.pos:type_name -> cgeparser.Pos
+	13, // 12: cgeparser.Location.start:type_name -> cgeparser.Pos
+	13, // 13: cgeparser.Location.end:type_name -> cgeparser.Pos
+	14, // 14: cgeparser.Definition.location:type_name -> cgeparser.Location
+	14, // 15: cgeparser.References.locations:type_name -> cgeparser.Location
+	14, // 16: cgeparser.Hover.range:type_name -> cgeparser.Location
+	6,  // 17: cgeparser.Object.type:type_name -> cgeparser.Object.Type
+	20, // 18: cgeparser.Object.properties:type_name -> cgeparser.Property
+	6,  // 19: cgeparser.Symbol.kind:type_name -> cgeparser.Object.Type
+	13, // 20: cgeparser.Symbol.start:type_name -> cgeparser.Pos
+	13, // 21: cgeparser.Symbol.end:type_name -> cgeparser.Pos
+	14, // 22: cgeparser.Symbol.span:type_name -> cgeparser.Location
+	24, // 23: cgeparser.Property.type:type_name -> cgeparser.Property.Type
+	13, // 24: cgeparser.Diagnostic.Related.start:type_name -> cgeparser.Pos
+	13, // 25: cgeparser.Diagnostic.Related.end:type_name -> cgeparser.Pos
+	23, // 26: cgeparser.Diagnostic.Fix.edits:type_name -> cgeparser.Diagnostic.Fix.Edit
+	13, // 27: cgeparser.Diagnostic.Fix.Edit.start:type_name -> cgeparser.Pos
+	13, // 28: cgeparser.Diagnostic.Fix.Edit.end:type_name -> cgeparser.Pos
+	7,  // 29: cgeparser.Property.Type.type:type_name -> cgeparser.Property.Type.DataType
+	24, // 30: cgeparser.Property.Type.generic:type_name -> cgeparser.Property.Type
+	19, // 31: cgeparser.Property.Type.declaration:type_name -> cgeparser.Symbol
+	32, // [32:32] is the sub-list for method output_type
+	32, // [32:32] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_schema_proto_init() }
@@ -1053,7 +2021,7 @@ func file_schema_proto_init() {
 			}
 		}
 		file_schema_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Pos); i {
+			switch v := v.(*SemanticToken); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1065,7 +2033,7 @@ func file_schema_proto_init() {
 			}
 		}
 		file_schema_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Object); i {
+			switch v := v.(*Pos); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1077,7 +2045,7 @@ func file_schema_proto_init() {
 			}
 		}
 		file_schema_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Property); i {
+			switch v := v.(*Location); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1089,6 +2057,114 @@ func file_schema_proto_init() {
 			}
 		}
 		file_schema_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Definition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*References); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Hover); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Object); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Symbol); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Property); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Diagnostic_Related); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Diagnostic_Fix); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Diagnostic_Fix_Edit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_schema_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Property_Type); i {
 			case 0:
 				return &v.state
@@ -1101,15 +2177,15 @@ func file_schema_proto_init() {
 			}
 		}
 	}
-	file_schema_proto_msgTypes[5].OneofWrappers = []interface{}{}
-	file_schema_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_schema_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_schema_proto_msgTypes[12].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_schema_proto_rawDesc,
-			NumEnums:      5,
-			NumMessages:   8,
+			NumEnums:      8,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
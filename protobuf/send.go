@@ -9,6 +9,7 @@ import (
 
 	"github.com/code-game-project/cge-parser/parser"
 	"github.com/code-game-project/cge-parser/protobuf/schema"
+	"github.com/code-game-project/cge-parser/query"
 )
 
 type ProtobufSender struct {
@@ -33,19 +34,68 @@ func (p *ProtobufSender) SendMetadata(cgeVersion string) error {
 	return nil
 }
 
-func (p *ProtobufSender) SendDiagnostic(diagnosticType parser.DiagnosticType, message string, startLine, startColumn, endLine, endColumn int) error {
+func (p *ProtobufSender) SendDiagnostic(diagnostic parser.Diagnostic) error {
+	related := make([]*schema.Diagnostic_Related, 0, len(diagnostic.Related))
+	for _, r := range diagnostic.Related {
+		related = append(related, &schema.Diagnostic_Related{
+			Msg: r.Message,
+			Start: &schema.Pos{
+				Line:   int32(r.StartLine),
+				Column: int32(r.StartColumn),
+				Offset: int32(r.StartOffset),
+			},
+			End: &schema.Pos{
+				Line:   int32(r.EndLine),
+				Column: int32(r.EndColumn),
+				Offset: int32(r.EndOffset),
+			},
+		})
+	}
+
+	fixes := make([]*schema.Diagnostic_Fix, 0, len(diagnostic.Fixes))
+	for _, f := range diagnostic.Fixes {
+		edits := make([]*schema.Diagnostic_Fix_Edit, 0, len(f.Edits))
+		for _, e := range f.Edits {
+			edits = append(edits, &schema.Diagnostic_Fix_Edit{
+				Start: &schema.Pos{
+					Line:   int32(e.StartLine),
+					Column: int32(e.StartColumn),
+					Offset: int32(e.StartOffset),
+				},
+				End: &schema.Pos{
+					Line:   int32(e.EndLine),
+					Column: int32(e.EndColumn),
+					Offset: int32(e.EndOffset),
+				},
+				Text: e.Text,
+			})
+		}
+		fixes = append(fixes, &schema.Diagnostic_Fix{
+			Title: f.Title,
+			Edits: edits,
+			Safe:  f.Safe,
+		})
+	}
+
 	p.setMsgType(schema.MsgType_DIAGNOSTIC)
 	_, err := protodelim.MarshalTo(p.out, &schema.Diagnostic{
-		Type: schema.Diagnostic_Type(diagnosticType),
-		Msg:  message,
+		Type:     schema.Diagnostic_Type(diagnostic.Type),
+		Msg:      diagnostic.Message,
+		Code:     diagnostic.Code.String(),
+		Name:     diagnostic.Code.Name(),
+		Category: schema.Diagnostic_Category(diagnostic.Code.Category()),
 		Start: &schema.Pos{
-			Line:   int32(startLine),
-			Column: int32(startColumn),
+			Line:   int32(diagnostic.StartLine),
+			Column: int32(diagnostic.StartColumn),
+			Offset: int32(diagnostic.StartOffset),
 		},
 		End: &schema.Pos{
-			Line:   int32(endLine),
-			Column: int32(endColumn),
+			Line:   int32(diagnostic.EndLine),
+			Column: int32(diagnostic.EndColumn),
+			Offset: int32(diagnostic.EndOffset),
 		},
+		Related: related,
+		Fixes:   fixes,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to send diagnostic as protobuf message: %w", err)
@@ -53,14 +103,15 @@ func (p *ProtobufSender) SendDiagnostic(diagnosticType parser.DiagnosticType, me
 	return nil
 }
 
-func (p *ProtobufSender) SendToken(tokenType parser.TokenType, lexeme string, line, column int) error {
+func (p *ProtobufSender) SendToken(token parser.Token) error {
 	p.setMsgType(schema.MsgType_TOKEN)
 	_, err := protodelim.MarshalTo(p.out, &schema.Token{
-		Type:   schema.Token_Type(tokenType),
-		Lexeme: lexeme,
+		Type:   schema.Token_Type(token.Type),
+		Lexeme: token.Lexeme,
 		Pos: &schema.Pos{
-			Line:   int32(line),
-			Column: int32(column),
+			Line:   int32(token.Line),
+			Column: int32(token.Column),
+			Offset: int32(token.Offset),
 		},
 	})
 	if err != nil {
@@ -70,6 +121,26 @@ func (p *ProtobufSender) SendToken(tokenType parser.TokenType, lexeme string, li
 	return nil
 }
 
+func (p *ProtobufSender) SendSemanticToken(token parser.SemanticToken) error {
+	p.setMsgType(schema.MsgType_SEMANTIC_TOKEN)
+	_, err := protodelim.MarshalTo(p.out, &schema.SemanticToken{
+		Kind:       schema.SemanticToken_Kind(token.Kind),
+		Role:       schema.SemanticToken_Role(token.Role),
+		Deprecated: token.Deprecated,
+		Lexeme:     token.Token.Lexeme,
+		Pos: &schema.Pos{
+			Line:   int32(token.Token.Line),
+			Column: int32(token.Token.Column),
+			Offset: int32(token.Token.Offset),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode semantic token as protobuf message: %w", err)
+	}
+
+	return nil
+}
+
 func objectToProtobufObj(object parser.Object) *schema.Object {
 	var comment *string
 	if object.Comment != "" {
@@ -84,6 +155,7 @@ func objectToProtobufObj(object parser.Object) *schema.Object {
 		Name:       object.Name.Lexeme,
 		Properties: properties,
 		Comment:    comment,
+		Incomplete: object.Incomplete,
 	}
 }
 
@@ -122,10 +194,34 @@ func propertyTypeToProtobufPropType(propertyType *parser.PropertyType) *schema.P
 		generic = propertyTypeToProtobufPropType(propertyType.Generic)
 	}
 
+	var declaration *schema.Symbol
+	if propertyType.Declaration != nil {
+		declaration = symbolToProtobufSymbol(*propertyType.Declaration)
+	}
+
 	return &schema.Property_Type{
-		Name:    propertyType.Token.Lexeme,
-		Type:    pType,
-		Generic: generic,
+		Name:        propertyType.Token.Lexeme,
+		Type:        pType,
+		Generic:     generic,
+		Declaration: declaration,
+	}
+}
+
+func symbolToProtobufSymbol(symbol parser.Symbol) *schema.Symbol {
+	return &schema.Symbol{
+		Kind: schema.Object_Type(symbol.Kind - parser.TTConfig),
+		Name: symbol.Name.Lexeme,
+		Start: &schema.Pos{
+			Line:   int32(symbol.Name.Line),
+			Column: int32(symbol.Name.Column),
+			Offset: int32(symbol.Name.Offset),
+		},
+		End: &schema.Pos{
+			Line:   int32(symbol.Name.EndLine),
+			Column: int32(symbol.Name.EndColumn),
+			Offset: int32(symbol.Name.EndOffset),
+		},
+		Span: spanToProtobufLocation(symbol.Span),
 	}
 }
 
@@ -147,3 +243,76 @@ func (p *ProtobufSender) setMsgType(msgType schema.MsgType_Type) {
 		return
 	}
 }
+
+// SendDefinition sends the declaring identifier or an empty message if token is nil.
+func (p *ProtobufSender) SendDefinition(token *parser.Token) error {
+	definition := &schema.Definition{}
+	if token != nil {
+		definition.Name = token.Lexeme
+		definition.Location = tokenToProtobufLocation(*token)
+	}
+	p.setMsgType(schema.MsgType_DEFINITION)
+	_, err := protodelim.MarshalTo(p.out, definition)
+	if err != nil {
+		return fmt.Errorf("failed to send definition as protobuf message: %w", err)
+	}
+	return nil
+}
+
+func (p *ProtobufSender) SendReferences(tokens []parser.Token) error {
+	locations := make([]*schema.Location, 0, len(tokens))
+	for _, t := range tokens {
+		locations = append(locations, tokenToProtobufLocation(t))
+	}
+
+	p.setMsgType(schema.MsgType_REFERENCES)
+	_, err := protodelim.MarshalTo(p.out, &schema.References{
+		Locations: locations,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send references as protobuf message: %w", err)
+	}
+	return nil
+}
+
+// SendHover sends the hover information or an empty message if info is nil.
+func (p *ProtobufSender) SendHover(info *query.HoverInfo) error {
+	hover := &schema.Hover{}
+	if info != nil {
+		hover.Range = tokenToProtobufLocation(info.Token)
+		hover.Signature = info.Signature
+		hover.Doc = info.Doc
+	}
+	p.setMsgType(schema.MsgType_HOVER)
+	_, err := protodelim.MarshalTo(p.out, hover)
+	if err != nil {
+		return fmt.Errorf("failed to send hover as protobuf message: %w", err)
+	}
+	return nil
+}
+
+func spanToProtobufLocation(span parser.Span) *schema.Location {
+	return &schema.Location{
+		Start: &schema.Pos{
+			Line:   int32(span.StartLine),
+			Column: int32(span.StartColumn),
+		},
+		End: &schema.Pos{
+			Line:   int32(span.EndLine),
+			Column: int32(span.EndColumn),
+		},
+	}
+}
+
+func tokenToProtobufLocation(token parser.Token) *schema.Location {
+	return &schema.Location{
+		Start: &schema.Pos{
+			Line:   int32(token.Line),
+			Column: int32(token.Column),
+		},
+		End: &schema.Pos{
+			Line:   int32(token.EndLine),
+			Column: int32(token.EndColumn),
+		},
+	}
+}
@@ -0,0 +1,237 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/code-game-project/cge-parser/parser"
+)
+
+// Index maps positions to the declarations of a parsed file.
+type Index struct {
+	objects []parser.Object
+}
+
+// NewIndex returns an index of objects whose references are resolved like in the output of Parse and Tree.Objects.
+// References are looked up through PropertyType.Declaration.
+func NewIndex(objects []parser.Object) *Index {
+	return &Index{
+		objects: objects,
+	}
+}
+
+func (i *Index) Objects() []parser.Object {
+	return i.objects
+}
+
+type TargetKind int
+
+const (
+	TargetDeclaration TargetKind = iota
+	TargetReference
+	TargetProperty
+	TargetEnumMember
+)
+
+// Target is the AST node under a position.
+type Target struct {
+	Kind  TargetKind
+	Token parser.Token
+	// the declaration for TargetDeclaration and TargetReference (nil for undefined types),
+	// the containing object for TargetProperty and TargetEnumMember
+	Object   *parser.Object
+	Property *parser.Property
+}
+
+// TargetAt returns the identifier at the position.
+func (i *Index) TargetAt(line, column int) (Target, bool) {
+	for j := range i.objects {
+		o := &i.objects[j]
+		if o.Name.Lexeme != "" && ContainsPos(o.Name, line, column) {
+			return Target{Kind: TargetDeclaration, Token: o.Name, Object: o}, true
+		}
+		for k := range o.Properties {
+			p := &o.Properties[k]
+			if ContainsPos(p.NameToken, line, column) {
+				kind := TargetProperty
+				if o.Type == parser.TTEnum {
+					kind = TargetEnumMember
+				}
+				return Target{Kind: kind, Token: p.NameToken, Object: o, Property: p}, true
+			}
+		}
+	}
+
+	var result Target
+	found := false
+	i.ForEachReference(func(reference *parser.PropertyType) {
+		if !found && ContainsPos(reference.Token, line, column) {
+			result = Target{Kind: TargetReference, Token: reference.Token, Object: i.Declaration(reference.Declaration)}
+			found = true
+		}
+	})
+	return result, found
+}
+
+// Declaration returns the object declared by the symbol or nil if the symbol is nil.
+func (i *Index) Declaration(symbol *parser.Symbol) *parser.Object {
+	if symbol == nil {
+		return nil
+	}
+	for j := range i.objects {
+		if samePos(i.objects[j].Name, symbol.Name) {
+			return &i.objects[j]
+		}
+	}
+	return nil
+}
+
+// ForEachReference calls fn for every custom type used in a property type excluding inline declarations.
+// The declaration of undefined types is nil.
+func (i *Index) ForEachReference(fn func(reference *parser.PropertyType)) {
+	var walk func(t *parser.PropertyType)
+	walk = func(t *parser.PropertyType) {
+		if t == nil {
+			return
+		}
+		if t.Token.Type == parser.TTIdentifier && (t.Declaration == nil || !samePos(t.Declaration.Name, t.Token)) {
+			fn(t)
+		}
+		walk(t.Generic)
+	}
+	for _, o := range i.objects {
+		for _, p := range o.Properties {
+			walk(p.Type)
+		}
+	}
+}
+
+// References returns all uses of the declared type.
+func (i *Index) References(declaration *parser.Object) []parser.Token {
+	refs := make([]parser.Token, 0)
+	i.ForEachReference(func(reference *parser.PropertyType) {
+		if reference.Declaration != nil && samePos(reference.Declaration.Name, declaration.Name) {
+			refs = append(refs, reference.Token)
+		}
+	})
+	return refs
+}
+
+func samePos(a, b parser.Token) bool {
+	return a.Line == b.Line && a.Column == b.Column
+}
+
+// Definition returns the declaring identifier of the symbol at the position.
+func (i *Index) Definition(line, column int) (parser.Token, bool) {
+	t, ok := i.TargetAt(line, column)
+	if !ok {
+		return parser.Token{}, false
+	}
+	switch t.Kind {
+	case TargetDeclaration, TargetReference:
+		if t.Object == nil {
+			return parser.Token{}, false
+		}
+		return t.Object.Name, true
+	default:
+		return t.Token, true
+	}
+}
+
+// Occurrences returns the declaration of the symbol at the position followed by all of its references.
+func (i *Index) Occurrences(line, column int) ([]parser.Token, bool) {
+	t, ok := i.TargetAt(line, column)
+	if !ok {
+		return nil, false
+	}
+
+	switch t.Kind {
+	case TargetDeclaration, TargetReference:
+		if t.Object == nil {
+			return nil, false
+		}
+		tokens := []parser.Token{t.Object.Name}
+		if t.Object.Type == parser.TTType || t.Object.Type == parser.TTEnum {
+			tokens = append(tokens, i.References(t.Object)...)
+		}
+		return tokens, true
+	default:
+		return []parser.Token{t.Token}, true
+	}
+}
+
+// HoverInfo describes the symbol at a position.
+type HoverInfo struct {
+	// the hovered identifier
+	Token parser.Token
+	// the declaration in CGE syntax, e.g. 'type player' or 'name: string'
+	Signature string
+	// the doc comment without comment markers
+	Doc string
+}
+
+func (i *Index) Hover(line, column int) (HoverInfo, bool) {
+	t, ok := i.TargetAt(line, column)
+	if !ok {
+		return HoverInfo{}, false
+	}
+
+	var signature, comment string
+	switch t.Kind {
+	case TargetDeclaration, TargetReference:
+		if t.Object == nil {
+			return HoverInfo{}, false
+		}
+		signature = t.Object.Keyword()
+		if t.Object.Type != parser.TTConfig {
+			signature += " " + t.Object.Name.Lexeme
+		}
+		comment = t.Object.Comment
+	case TargetProperty:
+		signature = fmt.Sprintf("%s: %s", t.Property.Name, TypeString(t.Property.Type))
+		comment = t.Property.Comment
+	case TargetEnumMember:
+		signature = fmt.Sprintf("%s.%s", t.Object.Name.Lexeme, t.Property.Name)
+		comment = t.Property.Comment
+	}
+
+	return HoverInfo{
+		Token:     t.Token,
+		Signature: signature,
+		Doc:       CommentText(comment),
+	}, true
+}
+
+func ContainsPos(token parser.Token, line, column int) bool {
+	if line < token.Line || line > token.EndLine {
+		return false
+	}
+	if line == token.Line && column < token.Column {
+		return false
+	}
+	return line != token.EndLine || column <= token.EndColumn
+}
+
+func TypeString(t *parser.PropertyType) string {
+	if t == nil {
+		return ""
+	}
+	if t.Generic != nil {
+		return t.Token.Lexeme + "<" + TypeString(t.Generic) + ">"
+	}
+	return t.Token.Lexeme
+}
+
+// CommentText strips comment markers from a doc comment.
+func CommentText(comment string) string {
+	lines := strings.Split(comment, "\n")
+	for i, l := range lines {
+		l = strings.TrimSpace(l)
+		l = strings.TrimPrefix(l, "//")
+		l = strings.TrimPrefix(l, "/*")
+		l = strings.TrimSuffix(l, "*/")
+		l = strings.TrimPrefix(strings.TrimSpace(l), "*")
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,214 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/code-game-project/cge-parser/parser"
+)
+
+const fixture = `cge 0.5
+
+// A player.
+type player { username: string }
+
+enum team { red, blue }
+
+event joined {
+	players: list<map<player>>,
+	home: type base { team: team },
+	ghost: unknown,
+}
+`
+
+type objectCollector struct {
+	objects []parser.Object
+}
+
+func (o *objectCollector) SendMetadata(version string) error                  { return nil }
+func (o *objectCollector) SendDiagnostic(diagnostic parser.Diagnostic) error  { return nil }
+func (o *objectCollector) SendToken(token parser.Token) error                 { return nil }
+func (o *objectCollector) SendSemanticToken(token parser.SemanticToken) error { return nil }
+
+func (o *objectCollector) SendObject(object parser.Object) error {
+	o.objects = append(o.objects, object)
+	return nil
+}
+
+func newFixtureIndex(t *testing.T) *Index {
+	t.Helper()
+	collector := &objectCollector{}
+	err := parser.Parse(strings.NewReader(fixture), collector, parser.Config{IncludeComments: true, ErrorTolerant: true})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return NewIndex(collector.objects)
+}
+
+func tokenPos(token parser.Token) string {
+	return fmt.Sprintf("%d:%d", token.Line, token.Column)
+}
+
+func TestTargetAt(t *testing.T) {
+	index := newFixtureIndex(t)
+	tests := []struct {
+		name       string
+		line       int
+		column     int
+		want       bool
+		wantKind   TargetKind
+		wantToken  string
+		wantObject string
+	}{
+		{"declaration", 3, 5, true, TargetDeclaration, "3:5", "player"},
+		{"one past declaration", 3, 11, true, TargetDeclaration, "3:5", "player"},
+		{"after declaration", 3, 12, false, 0, "", ""},
+		{"property", 3, 14, true, TargetProperty, "3:14", "player"},
+		{"enum member", 5, 12, true, TargetEnumMember, "5:12", "team"},
+		{"reference in generics", 8, 19, true, TargetReference, "8:19", "player"},
+		{"one past reference in generics", 8, 25, true, TargetReference, "8:19", "player"},
+		{"builtin generic", 8, 15, false, 0, "", ""},
+		{"inline declaration", 9, 12, true, TargetDeclaration, "9:12", "base"},
+		{"property of inline declaration", 9, 19, true, TargetProperty, "9:19", "base"},
+		{"reference in inline declaration", 9, 25, true, TargetReference, "9:25", "team"},
+		{"undefined type", 10, 8, true, TargetReference, "10:8", ""},
+		{"keyword", 7, 0, false, 0, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := index.TargetAt(tt.line, tt.column)
+			if ok != tt.want {
+				t.Fatalf("TargetAt(%d, %d) ok = %t, want %t", tt.line, tt.column, ok, tt.want)
+			}
+			if !ok {
+				return
+			}
+			object := ""
+			if got.Object != nil {
+				object = got.Object.Name.Lexeme
+			}
+			if got.Kind != tt.wantKind || tokenPos(got.Token) != tt.wantToken || object != tt.wantObject {
+				t.Errorf("TargetAt(%d, %d) = %d %s %q, want %d %s %q", tt.line, tt.column, got.Kind, tokenPos(got.Token), object, tt.wantKind, tt.wantToken, tt.wantObject)
+			}
+		})
+	}
+}
+
+func TestDefinition(t *testing.T) {
+	index := newFixtureIndex(t)
+	tests := []struct {
+		name   string
+		line   int
+		column int
+		want   string
+	}{
+		{"declaration", 3, 8, "3:5"},
+		{"reference in generics", 8, 22, "3:5"},
+		{"one past reference in generics", 8, 25, "3:5"},
+		{"reference in inline declaration", 9, 29, "5:5"},
+		{"inline declaration", 9, 14, "9:12"},
+		{"property", 3, 14, "3:14"},
+		{"undefined type", 10, 10, ""},
+		{"whitespace", 1, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if token, ok := index.Definition(tt.line, tt.column); ok {
+				got = tokenPos(token)
+			}
+			if got != tt.want {
+				t.Errorf("Definition(%d, %d) = %q, want %q", tt.line, tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	index := newFixtureIndex(t)
+	tests := []struct {
+		name   string
+		line   int
+		column int
+		want   []string
+	}{
+		{"type declaration", 3, 8, []string{"3:5", "8:19"}},
+		{"reference in generics", 8, 19, []string{"3:5", "8:19"}},
+		{"enum referenced in inline declaration", 9, 25, []string{"5:5", "9:25"}},
+		{"one past enum declaration", 5, 9, []string{"5:5", "9:25"}},
+		{"inline declaration", 9, 12, []string{"9:12"}},
+		{"event", 7, 6, []string{"7:6"}},
+		{"enum member", 5, 18, []string{"5:17"}},
+		{"undefined type", 10, 8, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, ok := index.Occurrences(tt.line, tt.column)
+			if ok != (tt.want != nil) {
+				t.Fatalf("Occurrences(%d, %d) ok = %t, want %t", tt.line, tt.column, ok, tt.want != nil)
+			}
+			var got []string
+			for _, token := range tokens {
+				got = append(got, tokenPos(token))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Occurrences(%d, %d) = %v, want %v", tt.line, tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHover(t *testing.T) {
+	index := newFixtureIndex(t)
+	tests := []struct {
+		name          string
+		line          int
+		column        int
+		want          bool
+		wantToken     string
+		wantSignature string
+		wantDoc       string
+	}{
+		{"type declaration", 3, 5, true, "3:5", "type player", "A player."},
+		{"one past reference in generics", 8, 25, true, "8:19", "type player", "A player."},
+		{"property with generics", 8, 3, true, "8:1", "players: list<map<player>>", ""},
+		{"property of inline declaration", 9, 21, true, "9:19", "team: team", ""},
+		{"inline declaration", 9, 16, true, "9:12", "type base", ""},
+		{"enum member", 5, 18, true, "5:17", "team.blue", ""},
+		{"event", 7, 12, true, "7:6", "event joined", ""},
+		{"undefined type", 10, 8, false, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := index.Hover(tt.line, tt.column)
+			if ok != tt.want {
+				t.Fatalf("Hover(%d, %d) ok = %t, want %t", tt.line, tt.column, ok, tt.want)
+			}
+			if !ok {
+				return
+			}
+			if tokenPos(got.Token) != tt.wantToken || got.Signature != tt.wantSignature || got.Doc != tt.wantDoc {
+				t.Errorf("Hover(%d, %d) = %s %q %q, want %s %q %q", tt.line, tt.column, tokenPos(got.Token), got.Signature, got.Doc, tt.wantToken, tt.wantSignature, tt.wantDoc)
+			}
+		})
+	}
+}
+
+func TestResolvedDeclarations(t *testing.T) {
+	index := newFixtureIndex(t)
+	// without the resolved declaration the reference is undefined, although a type with its name exists
+	objects := index.Objects()
+	joined := &objects[len(objects)-2]
+	if joined.Name.Lexeme != "joined" {
+		t.Fatalf("object = %q, want joined", joined.Name.Lexeme)
+	}
+	joined.Properties[0].Type.Generic.Generic.Declaration = nil
+
+	if token, ok := index.Definition(8, 19); ok {
+		t.Errorf("Definition(8, 19) = %s, want none", tokenPos(token))
+	}
+	if tokens, _ := index.Occurrences(3, 5); len(tokens) != 1 {
+		t.Errorf("Occurrences(3, 5) = %d tokens, want only the declaration", len(tokens))
+	}
+}